@@ -1,12 +1,22 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"sort"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -14,16 +24,61 @@ import (
 	"github.com/OnyxDevTools/onyx-cli/internal/api"
 	"github.com/OnyxDevTools/onyx-cli/internal/codegen"
 	"github.com/OnyxDevTools/onyx-cli/internal/config"
+	"github.com/OnyxDevTools/onyx-cli/internal/devserver"
 	"github.com/OnyxDevTools/onyx-cli/internal/schema"
 )
 
 // Simple local build of the onyx CLI for workspace testing. Installs as `localonyx`.
-// Supports: info, init, gen, schema (get/validate/diff/publish).
+// Supports: info, init, gen, schema (get/validate/diff/publish), auth, config, dev server.
 
 func main() {
-	if err := newRootCmd().Execute(); err != nil {
+	// Cancel in-flight API requests (and pending retries) on Ctrl-C.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := newRootCmd().ExecuteContext(ctx)
+	stop()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		code, hint := exitStatus(err)
+		if hint != "" {
+			fmt.Fprintln(os.Stderr, "hint: "+hint)
+		}
+		os.Exit(code)
+	}
+}
+
+// Exit codes for API failures, so scripts can branch on the kind of error.
+const (
+	exitGeneric     = 1
+	exitAuth        = 3
+	exitNotFound    = 4
+	exitValidation  = 5
+	exitRateLimited = 6
+	exitServer      = 7
+)
+
+// exitStatus maps an error to a process exit code and a remediation hint.
+func exitStatus(err error) (int, string) {
+	var apiErr *api.APIError
+	if !errors.As(err, &apiErr) {
+		return exitGeneric, ""
+	}
+	switch apiErr.Category {
+	case api.CategoryAuth:
+		return exitAuth, "the API key/secret were rejected; run onyx info to see where they were resolved from, or create a new key at https://cloud.onyx.dev > Database > Manage Database > API Keys"
+	case api.CategoryNotFound:
+		return exitNotFound, "the database (or revision) was not found; check databaseId with onyx info and that the API key belongs to that database"
+	case api.CategoryValidation:
+		return exitValidation, "the API rejected the request; run onyx schema lint for offline checks and fix the errors above"
+	case api.CategoryRateLimited:
+		return exitRateLimited, "rate limited by the Onyx API; wait and retry, or raise --retries"
+	case api.CategoryServer:
+		hint := "the Onyx API returned a server error; retry later"
+		if apiErr.RequestID != "" {
+			hint += " and include request id " + apiErr.RequestID + " if you contact support"
+		}
+		return exitServer, hint
+	default:
+		return exitGeneric, ""
 	}
 }
 
@@ -43,11 +98,17 @@ func newRootCmd() *cobra.Command {
 	cmd.PersistentFlags().StringVar(&cfgFlags.AIBaseURL, "ai-base-url", "", "Onyx AI base URL")
 	cmd.PersistentFlags().StringVar(&cfgFlags.DefaultModel, "default-model", "", "Default model name")
 	cmd.PersistentFlags().StringVar(&cfgFlags.ConfigPath, "config", "", "Path to onyx config file (overrides search chain)")
+	cmd.PersistentFlags().StringVar(&cfgFlags.Profile, "profile", "", "Named profile from the config file or ~/.onyx/profiles.json (overrides ONYX_PROFILE)")
+	cmd.PersistentFlags().DurationVar(&cfgFlags.Timeout, "timeout", api.DefaultTimeout, "Timeout for each Schema API request")
+	cmd.PersistentFlags().IntVar(&cfgFlags.Retries, "retries", api.DefaultRetryPolicy.MaxRetries, "Retries for transient Schema API failures (429, 5xx, connection resets)")
 
 	cmd.AddCommand(newInfoCmd(&cfgFlags))
 	cmd.AddCommand(newInitCmd())
 	cmd.AddCommand(newGenCmd(&cfgFlags))
 	cmd.AddCommand(newSchemaCmd(&cfgFlags))
+	cmd.AddCommand(newDevCmd(&cfgFlags))
+	cmd.AddCommand(newAuthCmd(&cfgFlags))
+	cmd.AddCommand(newConfigCmd(&cfgFlags))
 	cmd.AddCommand(newVersionCmd())
 
 	return cmd
@@ -61,6 +122,9 @@ type cfgOptions struct {
 	AIBaseURL    string
 	DefaultModel string
 	ConfigPath   string
+	Profile      string
+	Timeout      time.Duration
+	Retries      int
 }
 
 func resolveCfg(opts *cfgOptions) (config.ResolvedConfig, error) {
@@ -72,17 +136,35 @@ func resolveCfg(opts *cfgOptions) (config.ResolvedConfig, error) {
 		AIBaseURL:    opts.AIBaseURL,
 		DefaultModel: opts.DefaultModel,
 		ConfigPath:   opts.ConfigPath,
+		Profile:      opts.Profile,
 	})
 }
 
+// newAPIClient builds a Schema API client from resolved credentials and the --timeout/--retries flags.
+func newAPIClient(opts *cfgOptions, rc config.ResolvedConfig) *api.Client {
+	policy := api.DefaultRetryPolicy
+	if opts.Retries >= 0 {
+		policy.MaxRetries = opts.Retries
+	}
+	return api.NewClient(rc.BaseURL.Value, rc.DatabaseID.Value, rc.APIKey.Value, rc.APISecret.Value,
+		api.WithTimeout(opts.Timeout),
+		api.WithRetryPolicy(policy),
+	)
+}
+
+// resolveCfgOrGuide resolves the config including credentials (see config.FillCredentials) for
+// commands that call the API, with setup guidance when they are missing.
 func resolveCfgOrGuide(opts *cfgOptions) (config.ResolvedConfig, error) {
 	rc, err := resolveCfg(opts)
 	if err != nil {
 		return rc, err
 	}
+	if err := rc.FillCredentials(); err != nil {
+		return rc, err
+	}
 	if err := rc.Validate(); err != nil {
 		return rc, fmt.Errorf(
-			"Onyx credentials not found (%v).\n\nTo fix:\n  1) Visit https://cloud.onyx.dev > Database > Manage Database > API Keys and create an API key.\n  2) Either download the onyx-database.json config file into your project (or point ONYX_CONFIG_PATH to it),\n     or set these environment variables:\n       - ONYX_DATABASE_ID\n       - ONYX_DATABASE_BASE_URL\n       - ONYX_DATABASE_API_KEY\n       - ONYX_DATABASE_API_SECRET\n",
+			"Onyx credentials not found (%v).\n\nTo fix:\n  1) Visit https://cloud.onyx.dev > Database > Manage Database > API Keys and create an API key.\n  2) Either download the onyx-database.json config file into your project (or point ONYX_CONFIG_PATH to it),\n     or set these environment variables:\n       - ONYX_DATABASE_ID\n       - ONYX_DATABASE_BASE_URL\n       - ONYX_DATABASE_API_KEY\n       - ONYX_DATABASE_API_SECRET\n  3) Or run onyx auth login to keep the key/secret encrypted in ~/.onyx instead of project files.\n",
 			err,
 		)
 	}
@@ -91,22 +173,187 @@ func resolveCfgOrGuide(opts *cfgOptions) (config.ResolvedConfig, error) {
 
 // INFO ---------------------------------------------------------------------
 func newInfoCmd(cfg *cfgOptions) *cobra.Command {
-	return &cobra.Command{
+	var asJSON, skipCheck bool
+	cmd := &cobra.Command{
 		Use:   "info",
-		Short: "Show resolved configuration",
+		Short: "Show resolved configuration, config search paths and a Schema API connectivity check",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			resolved, err := resolveCfg(cfg)
 			if err != nil {
 				return err
 			}
+			// the credential helper only runs for the connectivity check, and its failure is reported there
+			var credErr error
+			if !skipCheck {
+				credErr = resolved.FillCredentials()
+			}
+			report := buildInfoReport(cfg, resolved)
+			switch {
+			case skipCheck:
+				report.Connection.Skipped = "--skip-check"
+			case credErr != nil:
+				report.Connection.Skipped = credErr.Error()
+			default:
+				report.Connection = checkConnection(cmd.Context(), cfg, resolved)
+			}
+			if asJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
 			printResolvedConfig(cmd, resolved)
+			printInfoReport(cmd, report)
 			return nil
 		},
 	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the report as JSON")
+	cmd.Flags().BoolVar(&skipCheck, "skip-check", false, "Do not contact the Schema API")
+	return cmd
+}
+
+// infoReport is the `onyx info --json` document. apiSecret is masked.
+type infoReport struct {
+	Profile     *infoValue           `json:"profile,omitempty"`
+	Config      map[string]infoValue `json:"config"`
+	ConfigFile  string               `json:"configFile,omitempty"`
+	Searched    bool                 `json:"searched"` // false when --config/ONYX_CONFIG_PATH bypasses the search
+	SearchPaths []infoCandidate      `json:"searchPaths"`
+	Connection  connectionCheck      `json:"connection"`
+}
+
+type infoValue struct {
+	Value  string `json:"value"`
+	Source string `json:"source,omitempty"`
+}
+
+type infoCandidate struct {
+	config.Candidate
+	InUse bool `json:"inUse,omitempty"`
+}
+
+// connectionCheck is the result of one unretried GET /schemas/{databaseId}.
+type connectionCheck struct {
+	Checked    bool   `json:"checked"`
+	OK         bool   `json:"ok"`
+	Skipped    string `json:"skipped,omitempty"` // reason the check did not run
+	URL        string `json:"url,omitempty"`
+	LatencyMS  int64  `json:"latencyMs,omitempty"`
+	HTTPStatus int    `json:"httpStatus,omitempty"`
+	Auth       string `json:"auth,omitempty"` // valid, invalid or unknown
+	RevisionID string `json:"revisionId,omitempty"`
+	Tables     int    `json:"tables,omitempty"`
+	RequestID  string `json:"requestId,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func buildInfoReport(cfg *cfgOptions, rc config.ResolvedConfig) infoReport {
+	report := infoReport{Config: map[string]infoValue{}, ConfigFile: rc.ConfigFile}
+	if rc.Profile.Value != "" {
+		report.Profile = &infoValue{Value: rc.Profile.Value, Source: rc.Profile.Source}
+	}
+	for _, key := range config.Keys {
+		rv, _ := rc.Lookup(key)
+		if rv.Value == "" {
+			continue
+		}
+		if config.IsSecretKey(key) {
+			rv.Value = config.MaskSecret(rv.Value)
+		}
+		report.Config[key] = infoValue{Value: rv.Value, Source: rv.Source}
+	}
+
+	// the search only uses a database ID that was known before any file was read
+	searchID := ""
+	if rc.DatabaseID.Source == "flag" || rc.DatabaseID.Source == "env" {
+		searchID = rc.DatabaseID.Value
+	}
+	report.Searched = cfg.ConfigPath == "" && os.Getenv("ONYX_CONFIG_PATH") == ""
+	wd, _ := os.Getwd()
+	for _, c := range config.InspectSearchPaths(wd, searchID) {
+		report.SearchPaths = append(report.SearchPaths, infoCandidate{Candidate: c, InUse: c.Path == rc.ConfigFile})
+	}
+	return report
+}
+
+func checkConnection(ctx context.Context, cfg *cfgOptions, rc config.ResolvedConfig) connectionCheck {
+	if err := rc.Validate(); err != nil {
+		return connectionCheck{Skipped: err.Error()}
+	}
+	check := connectionCheck{
+		Checked: true,
+		URL:     strings.TrimRight(rc.BaseURL.Value, "/") + "/schemas/" + rc.DatabaseID.Value,
+	}
+	client := api.NewClient(rc.BaseURL.Value, rc.DatabaseID.Value, rc.APIKey.Value, rc.APISecret.Value,
+		api.WithTimeout(cfg.Timeout),
+		api.WithRetryPolicy(api.RetryPolicy{}),
+	)
+	start := time.Now()
+	rev, err := client.GetSchemaContext(ctx, nil)
+	check.LatencyMS = time.Since(start).Milliseconds()
+	if err == nil {
+		check.OK = true
+		check.HTTPStatus = http.StatusOK
+		check.Auth = "valid"
+		check.Tables = len(rev.Tables)
+		if rev.Meta != nil {
+			check.RevisionID = rev.Meta.RevisionID
+		}
+		return check
+	}
+	check.Error = err.Error()
+	check.Auth = "unknown"
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) {
+		check.HTTPStatus = apiErr.StatusCode
+		check.RequestID = apiErr.RequestID
+		if apiErr.Category == api.CategoryAuth {
+			check.Auth = "invalid"
+		}
+	}
+	return check
+}
+
+func printInfoReport(cmd *cobra.Command, report infoReport) {
+	out := cmd.OutOrStdout()
+	if report.Searched {
+		fmt.Fprintln(out, "config search:")
+	} else {
+		fmt.Fprintln(out, "config search (skipped: --config/ONYX_CONFIG_PATH in use):")
+	}
+	tw := tabwriter.NewWriter(out, 0, 2, 2, ' ', 0)
+	for _, c := range report.SearchPaths {
+		line := "  " + c.Status + "\t" + c.Path
+		if c.InUse {
+			line += " (in use)"
+		}
+		if c.Error != "" {
+			line += ": " + c.Error
+		}
+		fmt.Fprintln(tw, line)
+	}
+	_ = tw.Flush()
+
+	c := report.Connection
+	switch {
+	case !c.Checked:
+		fmt.Fprintf(out, "connection:   skipped (%s)\n", c.Skipped)
+	case c.OK:
+		revision := firstNonBlank(c.RevisionID, "unknown")
+		fmt.Fprintf(out, "connection:   ok (HTTP %d, %dms, auth valid, revision %s, %d tables)\n", c.HTTPStatus, c.LatencyMS, revision, c.Tables)
+	default:
+		status := "no response"
+		if c.HTTPStatus != 0 {
+			status = fmt.Sprintf("HTTP %d", c.HTTPStatus)
+		}
+		fmt.Fprintf(out, "connection:   failed (%s, %dms, auth %s): %s\n", status, c.LatencyMS, c.Auth, c.Error)
+	}
 }
 
 func printResolvedConfig(cmd *cobra.Command, rc config.ResolvedConfig) {
 	out := cmd.OutOrStdout()
+	if rc.Profile.Value != "" {
+		fmt.Fprintf(out, "profile:      %s (source: %s)\n", rc.Profile.Value, rc.Profile.Source)
+	}
 	fmt.Fprintf(out, "databaseId:   %s (source: %s)\n", rc.DatabaseID.Value, rc.DatabaseID.Source)
 	fmt.Fprintf(out, "baseUrl:      %s (source: %s)\n", rc.BaseURL.Value, rc.BaseURL.Source)
 	fmt.Fprintf(out, "apiKey:       %s (source: %s)\n", rc.APIKey.Value, rc.APIKey.Source)
@@ -114,6 +361,9 @@ func printResolvedConfig(cmd *cobra.Command, rc config.ResolvedConfig) {
 	fmt.Fprintf(out, "aiBaseUrl:    %s (source: %s)\n", rc.AIBaseURL.Value, rc.AIBaseURL.Source)
 	fmt.Fprintf(out, "defaultModel: %s (source: %s)\n", rc.DefaultModel.Value, rc.DefaultModel.Source)
 	fmt.Fprintf(out, "codegenLang:  %s (source: %s)\n", rc.CodegenLang.Value, rc.CodegenLang.Source)
+	if rc.CredentialHelper.Value != "" {
+		fmt.Fprintf(out, "credHelper:   %s (source: %s)\n", rc.CredentialHelper.Value, rc.CredentialHelper.Source)
+	}
 	if rc.ConfigFile != "" {
 		fmt.Fprintf(out, "configFile:   %s\n", rc.ConfigFile)
 	}
@@ -155,43 +405,44 @@ func newInitCmd() *cobra.Command {
 }
 
 // GEN ----------------------------------------------------------------------
+
+// inferEmbeddedSampleSize is how many records per table --infer-embedded reads.
+const inferEmbeddedSampleSize = 100
+
 func newGenCmd(cfg *cfgOptions) *cobra.Command {
 	var schemaPath string
+	var source string
+	var tablesCSV string
 	var out string
 	var pkg string
 	var typeName string
 	var pointerFields bool
+	var inferEmbedded bool
+	var templatesDir string
 	var langGo, langTS, langPy, langJava, langKt bool
 
 	cmd := &cobra.Command{
 		Use:   "gen",
 		Short: "Generate client code from schema (local build)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if _, err := resolveCfgOrGuide(cfg); err != nil {
+			rc, err := resolveCfgOrGuide(cfg)
+			if err != nil {
 				return err
 			}
 
-			selectedSchema := schemaPath
-			if selectedSchema == "" {
-				selectedSchema = config.DefaultSchemaPath
+			data, err := loadGenSchema(cmd, cfg, rc, source, schemaPath, tablesCSV)
+			if err != nil {
+				return err
 			}
-
-			data, err := os.ReadFile(selectedSchema)
-			if err != nil && os.IsNotExist(err) {
-				if !cmd.Flags().Changed("schema") {
-					alt := filepath.Join("api", config.DefaultSchemaPath)
-					if altData, altErr := os.ReadFile(alt); altErr == nil {
-						selectedSchema = alt
-						data = altData
-						err = nil
-					} else {
-						err = altErr
-					}
+			if inferEmbedded {
+				client := newAPIClient(cfg, rc)
+				data, err = codegen.InferEmbeddedShapes(data, func(table string) ([]map[string]any, error) {
+					return client.QueryRecordsContext(cmd.Context(), table, inferEmbeddedSampleSize)
+				})
+				if err != nil {
+					return fmt.Errorf("infer embedded shapes: %w", err)
 				}
 			}
-			if err != nil {
-				return fmt.Errorf("read schema: %w", err)
-			}
 
 			// choose language
 			if !langGo && !langTS && !langPy && !langJava && !langKt {
@@ -209,10 +460,10 @@ func newGenCmd(cfg *cfgOptions) *cobra.Command {
 				if pkg == "" {
 					pkg = "onyx"
 				}
-				if err := codegen.RenderGoCommon(data, out, pkg, true, pointerFields); err != nil {
+				if err := codegen.RenderGoCommon(data, out, pkg, true, pointerFields, templatesDir); err != nil {
 					return err
 				}
-				if err := codegen.RenderGoTables(data, out, pkg, true, pointerFields); err != nil {
+				if err := codegen.RenderGoTables(data, out, pkg, true, pointerFields, templatesDir); err != nil {
 					return err
 				}
 				generated = append(generated, fmt.Sprintf("Go client -> %s (package %s)", out, pkg))
@@ -302,11 +553,15 @@ func newGenCmd(cfg *cfgOptions) *cobra.Command {
 		},
 	}
 
+	cmd.Flags().StringVar(&source, "source", "file", "Schema source: auto (file, then API), api, or file")
 	cmd.Flags().StringVar(&schemaPath, "schema", config.DefaultSchemaPath, "Schema file path")
+	cmd.Flags().StringVar(&tablesCSV, "tables", "", "Comma-separated tables to generate (filters the file schema or the API fetch)")
 	cmd.Flags().StringVar(&out, "out", "", "Output path (language-specific default if empty)")
 	cmd.Flags().StringVar(&pkg, "package", "", "Go/Java/Kotlin package name")
 	cmd.Flags().StringVar(&typeName, "name", "", "TypeScript export type name (default OnyxSchema)")
 	cmd.Flags().BoolVar(&pointerFields, "go-pointer-fields", false, "Generate Go struct fields as pointers when nullable")
+	cmd.Flags().BoolVar(&inferEmbedded, "infer-embedded", false, "Infer embedded object types from sample records (API); schema shapes take precedence")
+	cmd.Flags().StringVar(&templatesDir, "templates", "", "Directory of *.tmpl files overriding the embedded Go templates (e.g. model.tmpl)")
 	cmd.Flags().BoolVar(&langGo, "go", false, "Generate Go client")
 	cmd.Flags().BoolVar(&langGo, "golang", false, "Generate Go client (alias)")
 	cmd.Flags().BoolVar(&langTS, "ts", false, "Generate TypeScript types")
@@ -319,6 +574,154 @@ func newGenCmd(cfg *cfgOptions) *cobra.Command {
 	return cmd
 }
 
+// loadGenSchema returns the schema JSON used for codegen based on --source.
+// file reads the local schema (falling back to ./api/onyx.schema.json when --schema is not set),
+// api fetches the latest revision, and auto prefers the local file and falls back to the API.
+func loadGenSchema(cmd *cobra.Command, cfg *cfgOptions, rc config.ResolvedConfig, source, schemaPath, tablesCSV string) ([]byte, error) {
+	mode := strings.ToLower(strings.TrimSpace(source))
+	if mode == "" {
+		mode = "file"
+	}
+	switch mode {
+	case "file":
+		data, err := readGenSchemaFile(cmd, schemaPath)
+		if err != nil {
+			return nil, err
+		}
+		return filterSchemaTables(data, tablesCSV)
+	case "api":
+		return fetchGenSchema(cmd.Context(), newAPIClient(cfg, rc), tablesCSV)
+	case "auto":
+		data, err := readGenSchemaFile(cmd, schemaPath)
+		if err == nil {
+			return filterSchemaTables(data, tablesCSV)
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		return fetchGenSchema(cmd.Context(), newAPIClient(cfg, rc), tablesCSV)
+	default:
+		return nil, fmt.Errorf("invalid --source %q (expected auto, api, or file)", source)
+	}
+}
+
+// readGenSchemaFile reads the local schema as JSON, trying ./api/onyx.schema.json when --schema was
+// not set explicitly. Default paths also match .yaml/.yml/.toml variants; split schema directories
+// are assembled from their manifest.
+func readGenSchemaFile(cmd *cobra.Command, schemaPath string) ([]byte, error) {
+	selectedSchema := schemaPath
+	if selectedSchema == "" {
+		selectedSchema = config.DefaultSchemaPath
+	}
+	explicit := cmd.Flags().Changed("schema")
+	if !explicit {
+		selectedSchema = existingSchemaVariant(selectedSchema)
+	}
+
+	data, _, _, err := readSchemaFile(selectedSchema)
+	if err != nil && errors.Is(err, fs.ErrNotExist) {
+		if !explicit {
+			alt := existingSchemaVariant(filepath.Join("api", config.DefaultSchemaPath))
+			data, _, _, err = readSchemaFile(alt)
+		}
+	}
+	return data, err
+}
+
+// existingSchemaVariant returns path, or its first existing .yaml/.yml/.toml sibling when
+// path is a missing .json file.
+func existingSchemaVariant(path string) string {
+	if _, err := os.Stat(path); err == nil || filepath.Ext(path) != ".json" {
+		return path
+	}
+	base := strings.TrimSuffix(path, ".json")
+	for _, ext := range []string{".yaml", ".yml", ".toml"} {
+		if _, err := os.Stat(base + ext); err == nil {
+			return base + ext
+		}
+	}
+	return path
+}
+
+// fetchGenSchema pulls the latest schema from the API, optionally limited to specific tables.
+func fetchGenSchema(ctx context.Context, client *api.Client, tablesCSV string) ([]byte, error) {
+	var tables []string
+	if strings.TrimSpace(tablesCSV) != "" {
+		tables = strings.Split(tablesCSV, ",")
+	}
+	raw, rev, err := client.GetSchemaRawContext(ctx, tables)
+	if err != nil {
+		return nil, fmt.Errorf("fetch schema: %w", err)
+	}
+	if len(bytes.TrimSpace(raw)) == 0 {
+		if rev == nil {
+			return nil, fmt.Errorf("fetch schema: empty response from API")
+		}
+		encoded, err := json.Marshal(rev)
+		if err != nil {
+			return nil, fmt.Errorf("encode schema: %w", err)
+		}
+		return encoded, nil
+	}
+	data, err := sanitizeSchemaJSON(raw)
+	if err != nil {
+		return nil, fmt.Errorf("sanitize schema: %w", err)
+	}
+	return data, nil
+}
+
+// filterSchemaTables keeps only the tables named in tablesCSV (all tables when it is empty), the
+// file-source counterpart of the API's ?tables= filter. Unknown names are an error so typos are not
+// silently dropped.
+func filterSchemaTables(data []byte, tablesCSV string) ([]byte, error) {
+	want := map[string]bool{}
+	for _, name := range strings.Split(tablesCSV, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			want[name] = false
+		}
+	}
+	if len(want) == 0 {
+		return data, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode schema json: %w", err)
+	}
+	for _, key := range []string{"tables", "entities"} {
+		list, ok := doc[key].([]any)
+		if !ok {
+			continue
+		}
+		kept := []any{}
+		for _, item := range list {
+			table, _ := item.(map[string]any)
+			name, _ := table["name"].(string)
+			if _, ok := want[name]; ok {
+				want[name] = true
+				kept = append(kept, item)
+			}
+		}
+		doc[key] = kept
+	}
+	var missing []string
+	for name, found := range want {
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("--tables: %s not found in the schema file", strings.Join(missing, ", "))
+	}
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("encode schema json: %w", err)
+	}
+	return out, nil
+}
+
 // ensureDirWritable creates the directory if missing; errors if a non-dir exists.
 func ensureDirWritable(dir string) error {
 	info, err := os.Stat(dir)
@@ -344,21 +747,24 @@ func newSchemaCmd(cfg *cfgOptions) *cobra.Command {
 	var schemaPath string
 	var tablesCSV string
 	var publish bool
-	var getOutPath string
-	var getTablesCSV string
-	var printOnly bool
+	var getOpts schemaGetOptions
+	var failOn string
+	var diffFormat string
 
 	root := &cobra.Command{
 		Use:   "schema",
-		Short: "Schema operations (get/validate/diff/publish)",
+		Short: "Schema operations (get/history/validate/lint/diff/publish/rollback)",
 		Args:  cobra.MaximumNArgs(1), // optional file path when using default get behavior
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runSchemaGet(cmd, cfg, getOutPath, getTablesCSV, printOnly, args)
+			return runSchemaGet(cmd, cfg, getOpts, args)
 		},
 	}
-	root.Flags().StringVar(&getOutPath, "out", "", "Output file path (default ./onyx.schema.json)")
-	root.Flags().StringVar(&getTablesCSV, "tables", "", "Comma-separated tables to fetch (prints to stdout)")
-	root.Flags().BoolVar(&printOnly, "print", false, "Print schema to stdout instead of writing file")
+	root.Flags().StringVar(&getOpts.OutPath, "out", "", "Output file path (default ./onyx.schema.json)")
+	root.Flags().StringVar(&getOpts.TablesCSV, "tables", "", "Comma-separated tables to fetch (prints to stdout)")
+	root.Flags().BoolVar(&getOpts.PrintOnly, "print", false, "Print schema to stdout instead of writing file")
+	root.Flags().StringVar(&getOpts.Revision, "revision", "", "Fetch a specific revision ID (see onyx schema history) instead of the latest")
+	root.Flags().StringVar(&getOpts.Format, "format", "", "Output format: json, yaml or toml (default from the file extension, else json)")
+	root.Flags().StringVar(&getOpts.SplitDir, "split", "", "Write a manifest plus one file per table (tables/<Name>.<format>) into this directory")
 
 	// get
 	get := &cobra.Command{
@@ -366,12 +772,37 @@ func newSchemaCmd(cfg *cfgOptions) *cobra.Command {
 		Short: "Fetch latest schema from the API",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runSchemaGet(cmd, cfg, getOutPath, getTablesCSV, printOnly, args)
+			return runSchemaGet(cmd, cfg, getOpts, args)
+		},
+	}
+	get.Flags().StringVar(&getOpts.OutPath, "out", "", "Output file path (default ./onyx.schema.json)")
+	get.Flags().StringVar(&getOpts.TablesCSV, "tables", "", "Comma-separated tables to fetch (prints to stdout)")
+	get.Flags().BoolVar(&getOpts.PrintOnly, "print", false, "Print schema to stdout instead of writing file")
+	get.Flags().StringVar(&getOpts.Revision, "revision", "", "Fetch a specific revision ID (see onyx schema history) instead of the latest")
+	get.Flags().StringVar(&getOpts.Format, "format", "", "Output format: json, yaml or toml (default from the file extension, else json)")
+	get.Flags().StringVar(&getOpts.SplitDir, "split", "", "Write a manifest plus one file per table (tables/<Name>.<format>) into this directory")
+
+	// history
+	var historyJSON bool
+	history := &cobra.Command{
+		Use:   "history",
+		Short: "List schema revisions (newest first)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rc, err := resolveCfgOrGuide(cfg)
+			if err != nil {
+				return err
+			}
+			client := newAPIClient(cfg, rc)
+			revs, err := client.ListSchemaRevisionsContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			sort.SliceStable(revs, func(i, j int) bool { return revisionCreatedAt(revs[i]) > revisionCreatedAt(revs[j]) })
+			return printSchemaHistory(cmd, revs, historyJSON)
 		},
 	}
-	get.Flags().StringVar(&getOutPath, "out", "", "Output file path (default ./onyx.schema.json)")
-	get.Flags().StringVar(&getTablesCSV, "tables", "", "Comma-separated tables to fetch (prints to stdout)")
-	get.Flags().BoolVar(&printOnly, "print", false, "Print schema to stdout instead of writing file")
+	history.Flags().BoolVar(&historyJSON, "json", false, "Print revisions as JSON")
 
 	// validate
 	validate := &cobra.Command{
@@ -388,12 +819,12 @@ func newSchemaCmd(cfg *cfgOptions) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			client := api.NewClient(rc.BaseURL.Value, rc.DatabaseID.Value, rc.APIKey.Value, rc.APISecret.Value)
-			sanitized, err := sanitizeSchemaJSON(rawSchema)
+			client := newAPIClient(cfg, rc)
+			apiReady, err := normalizeSchemaForAPI(rawSchema)
 			if err != nil {
 				return fmt.Errorf("sanitize schema: %w", err)
 			}
-			res, err := client.ValidateSchemaRaw(sanitized)
+			res, err := client.ValidateSchemaRawContext(cmd.Context(), apiReady)
 			if err != nil {
 				return err
 			}
@@ -408,46 +839,140 @@ func newSchemaCmd(cfg *cfgOptions) *cobra.Command {
 	}
 	validate.Flags().StringVar(&schemaPath, "schema", config.DefaultSchemaPath, "Local schema file to validate")
 
-	// diff
-	diff := &cobra.Command{
-		Use:   "diff [file]",
-		Short: "Diff API schema vs local schema",
+	// lint (offline)
+	lint := &cobra.Command{
+		Use:   "lint [file]",
+		Short: "Check local schema for common mistakes (offline)",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			schemaFile := pickSchemaPath(schemaPath, args)
-			localReq, filePath, _, err := loadLocalSchema(schemaFile)
+			data, filePath, sources, err := readSchemaFile(schemaFile)
 			if err != nil {
 				return err
 			}
-			rc, err := resolveCfgOrGuide(cfg)
+			req, _, _, err := parseSchemaJSON(data, filePath)
+			if err != nil {
+				return err
+			}
+			issues := schema.LintSchema(req, schema.LintOptions{File: filePath, TablesKey: schemaTablesKey(filePath), Sources: sources})
+			out := cmd.OutOrStdout()
+			if len(issues) == 0 {
+				fmt.Fprintf(out, "No lint issues found in %s.\n", filePath)
+				return nil
+			}
+			for _, issue := range issues {
+				fmt.Fprintf(out, "%s: %s [%s] %s\n", issue.Location(), issue.Severity, issue.Rule, issue.Message)
+			}
+			errCount, warnCount := schema.CountLintIssues(issues)
+			fmt.Fprintf(out, "%d error(s), %d warning(s)\n", errCount, warnCount)
+			if errCount > 0 {
+				return fmt.Errorf("schema lint failed with %d error(s); fix the issues above and re-run onyx schema lint", errCount)
+			}
+			return nil
+		},
+	}
+	lint.Flags().StringVar(&schemaPath, "schema", config.DefaultSchemaPath, "Local schema file to lint")
+
+	// jsonschema (offline)
+	var jsonSchemaOut string
+	jsonSchemaCmd := &cobra.Command{
+		Use:   "jsonschema",
+		Short: "Print a JSON Schema (draft 2020-12) for onyx.schema.json files, for editor validation",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := schema.FileJSONSchema()
 			if err != nil {
 				return err
 			}
-			client := api.NewClient(rc.BaseURL.Value, rc.DatabaseID.Value, rc.APIKey.Value, rc.APISecret.Value)
+			if jsonSchemaOut == "" {
+				_, err := cmd.OutOrStdout().Write(data)
+				return err
+			}
+			if dir := filepath.Dir(jsonSchemaOut); dir != "." {
+				if err := os.MkdirAll(dir, 0o755); err != nil {
+					return fmt.Errorf("create directory %s: %w", dir, err)
+				}
+			}
+			if err := os.WriteFile(jsonSchemaOut, data, 0o644); err != nil {
+				return fmt.Errorf("write %s: %w", jsonSchemaOut, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Wrote JSON Schema to %s\n", jsonSchemaOut)
+			return nil
+		},
+	}
+	jsonSchemaCmd.Flags().StringVar(&jsonSchemaOut, "out", "", "Write the JSON Schema to this file instead of stdout")
+
+	// fmt (offline)
+	var fmtCheck bool
+	fmtCmd := &cobra.Command{
+		Use:   "fmt [file]",
+		Short: "Rewrite local schema files in canonical order and casing (offline)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSchemaFmt(cmd, pickSchemaPath(schemaPath, args), fmtCheck)
+		},
+	}
+	fmtCmd.Flags().StringVar(&schemaPath, "schema", config.DefaultSchemaPath, "Local schema file (or split schema directory) to format")
+	fmtCmd.Flags().BoolVar(&fmtCheck, "check", false, "Report files that are not canonically formatted and exit non-zero instead of rewriting them")
+
+	// diff
+	diff := &cobra.Command{
+		Use:   "diff [file] | diff <left> <right>",
+		Short: "Diff API schema vs local schema, or any two schema sources",
+		Long: `Diff API schema vs local schema, or any two schema sources.
+
+With zero or one argument, compares the API schema against a local file
+(default ./onyx.schema.json). With two arguments, compares <left> (baseline)
+against <right>. Each side may be:
+  <path>                 a local schema file
+  api                    the configured database's latest schema
+  db:<databaseId>        another database's latest schema (same credentials)
+  rev:<revisionId>       a schema revision from onyx schema history
+  git:<ref>:<path>       a schema file at a git revision, e.g. git:main:api/onyx.schema.json`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
 			var tables []string
 			if strings.TrimSpace(tablesCSV) != "" {
 				tables = strings.Split(tablesCSV, ",")
 			}
-			apiSchema, err := client.GetSchema(tables)
+			leftSpec, rightSpec := "api", pickSchemaPath(schemaPath, args)
+			if len(args) == 2 {
+				leftSpec, rightSpec = args[0], args[1]
+			}
+			// Load the right side first so local file errors surface before credential errors.
+			right, err := loadSchemaSource(cmd.Context(), cfg, rightSpec, tables)
 			if err != nil {
 				return err
 			}
-			diff := schema.ComputeSchemaDiff(*apiSchema, localReq)
-			fmt.Fprint(cmd.OutOrStdout(), schema.FormatSchemaDiff(diff, filePath))
-			return nil
+			left, err := loadSchemaSource(cmd.Context(), cfg, leftSpec, tables)
+			if err != nil {
+				return err
+			}
+			diff := schema.ComputeSchemaDiff(schema.SchemaRevision{DatabaseID: left.Schema.DatabaseID, Tables: left.Schema.Tables, Meta: left.Schema.Meta}, right.Schema)
+			labels := schema.DiffLabels{Left: left.Label, Right: right.Label, File: right.File}
+			rendered, err := schema.FormatSchemaDiffAs(diff, labels, diffFormat)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), rendered)
+			return checkDiffImpact(cmd, diff, failOn)
 		},
 	}
-	diff.Flags().StringVar(&schemaPath, "schema", config.DefaultSchemaPath, "Local schema file to compare")
-	diff.Flags().StringVar(&tablesCSV, "tables", "", "Comma-separated tables to fetch from API")
+	diff.Flags().StringVar(&schemaPath, "schema", config.DefaultSchemaPath, "Local schema file to compare (single-source mode)")
+	diff.Flags().StringVar(&tablesCSV, "tables", "", "Comma-separated tables to fetch from API sources")
+	diff.Flags().StringVar(&diffFormat, "format", schema.DiffFormatYAML, "Output format: yaml, json, markdown, or github (workflow annotations)")
+	diff.Flags().StringVar(&failOn, "fail-on", "", "Exit non-zero when changes reach this impact: breaking, potentially-breaking, or safe (any change)")
 
 	// publish (optional)
+	var publishYes bool
+	var dryRun bool
 	publishCmd := &cobra.Command{
 		Use:   "publish [file]",
 		Short: "Publish local schema to API",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			schemaFile := pickSchemaPath(schemaPath, args)
-			_, _, rawSchema, err := loadLocalSchema(schemaFile)
+			localReq, filePath, rawSchema, err := loadLocalSchema(schemaFile)
 			if err != nil {
 				return err
 			}
@@ -455,12 +980,47 @@ func newSchemaCmd(cfg *cfgOptions) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			client := api.NewClient(rc.BaseURL.Value, rc.DatabaseID.Value, rc.APIKey.Value, rc.APISecret.Value)
-			sanitized, err := sanitizeSchemaJSON(rawSchema)
+			client := newAPIClient(cfg, rc)
+			apiReady, err := normalizeSchemaForAPI(rawSchema)
 			if err != nil {
 				return fmt.Errorf("sanitize schema: %w", err)
 			}
-			rev, err := client.UpdateSchemaRaw(sanitized, publish)
+
+			out := cmd.OutOrStdout()
+			res, err := client.ValidateSchemaRawContext(cmd.Context(), apiReady)
+			if err != nil {
+				return err
+			}
+			if !boolValue(res.Valid) {
+				fmt.Fprintf(out, "valid: false (source: %s)\n", filePath)
+				for _, e := range res.Errors {
+					fmt.Fprintf(out, "- %s\n", strings.TrimSpace(e.Message))
+				}
+				return fmt.Errorf("schema %s failed validation; fix the errors above before publishing", filePath)
+			}
+
+			current, err := client.GetSchemaContext(cmd.Context(), nil)
+			if err != nil {
+				return err
+			}
+			diff := schema.ComputeSchemaDiff(*current, localReq)
+			fmt.Fprint(out, schema.FormatSchemaDiff(diff, filePath))
+
+			if dryRun {
+				fmt.Fprintf(out, "Dry run: schema is valid (impact: %s); nothing was published.\n", diff.Impact())
+				return nil
+			}
+			if diff.Impact() == schema.ImpactBreaking && !publishYes {
+				ok, err := confirmPrompt(cmd, fmt.Sprintf("Schema contains breaking changes. Publish to database %s anyway?", rc.DatabaseID.Value))
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errors.New("publish cancelled; nothing was published")
+				}
+			}
+
+			rev, err := client.UpdateSchemaRawContext(cmd.Context(), apiReady, publish)
 			if err != nil {
 				return err
 			}
@@ -469,73 +1029,330 @@ func newSchemaCmd(cfg *cfgOptions) *cobra.Command {
 			}
 
 			if publish {
-				fmt.Fprintln(cmd.OutOrStdout(), "Schema publish succeeded.")
+				fmt.Fprintln(out, "Schema publish succeeded.")
 			} else {
-				fmt.Fprintln(cmd.OutOrStdout(), "Schema saved (publish flag disabled).")
+				fmt.Fprintln(out, "Schema saved (publish flag disabled).")
 			}
 			return nil
 		},
 	}
 	publishCmd.Flags().StringVar(&schemaPath, "schema", config.DefaultSchemaPath, "Local schema file to publish")
 	publishCmd.Flags().BoolVar(&publish, "publish", true, "Whether to publish immediately")
+	publishCmd.Flags().BoolVar(&publishYes, "yes", false, "Publish breaking changes without prompting")
+	publishCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate and print the diff without publishing")
+
+	// rollback
+	var rollbackTo string
+	var rollbackYes bool
+	rollback := &cobra.Command{
+		Use:   "rollback --to <revisionId>",
+		Short: "Republish an earlier schema revision",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(rollbackTo) == "" {
+				return fmt.Errorf("--to is required (run onyx schema history to list revision IDs)")
+			}
+			rc, err := resolveCfgOrGuide(cfg)
+			if err != nil {
+				return err
+			}
+			client := newAPIClient(cfg, rc)
+			targetRaw, target, err := client.GetSchemaRevisionRawContext(cmd.Context(), rollbackTo)
+			if err != nil {
+				return err
+			}
+			current, err := client.GetSchemaContext(cmd.Context(), nil)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			diff := schema.ComputeSchemaDiff(*current, schema.SchemaUpsertRequest{Tables: target.Tables})
+			if len(diff.AllChanges()) == 0 {
+				fmt.Fprintf(out, "Current schema already matches revision %s; nothing to roll back.\n", rollbackTo)
+				return nil
+			}
+			fmt.Fprint(out, schema.FormatSchemaDiffBetween(diff, schema.DiffLabels{Left: "current API schema", Right: "revision " + rollbackTo}))
+			fmt.Fprintf(out, "\nImpact: %s\n", diff.Impact())
+
+			if !rollbackYes {
+				ok, err := confirmPrompt(cmd, fmt.Sprintf("Roll back database %s to revision %s?", rc.DatabaseID.Value, rollbackTo))
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errors.New("rollback cancelled; nothing was published")
+				}
+			}
+
+			body, err := revisionPublishPayload(targetRaw)
+			if err != nil {
+				return err
+			}
+			if _, err := client.UpdateSchemaRawContext(cmd.Context(), body, true); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Schema rolled back to revision %s.\n", rollbackTo)
+			return nil
+		},
+	}
+	rollback.Flags().StringVar(&rollbackTo, "to", "", "Revision ID to restore (see onyx schema history)")
+	rollback.Flags().BoolVar(&rollbackYes, "yes", false, "Skip the confirmation prompt")
 
-	root.AddCommand(get, validate, diff, publishCmd)
+	root.AddCommand(get, history, validate, lint, fmtCmd, jsonSchemaCmd, diff, publishCmd, rollback, newInfoCmd(cfg))
 	return root
 }
 
-func runSchemaGet(cmd *cobra.Command, cfg *cfgOptions, outPath, tablesCSV string, printOnly bool, args []string) error {
-	rc, err := resolveCfgOrGuide(cfg)
+// checkDiffImpact returns an error listing offending changes when the diff reaches the --fail-on level.
+func checkDiffImpact(cmd *cobra.Command, diff schema.SchemaDiff, failOn string) error {
+	if strings.TrimSpace(failOn) == "" || strings.EqualFold(strings.TrimSpace(failOn), "none") {
+		return nil
+	}
+	level, err := schema.ParseChangeImpact(failOn)
 	if err != nil {
-		return err
+		return fmt.Errorf("invalid --fail-on: %w", err)
+	}
+	offending := diff.ChangesAtLeast(level)
+	if len(offending) == 0 {
+		return nil
+	}
+	errOut := cmd.ErrOrStderr()
+	fmt.Fprintf(errOut, "Changes at or above %s:\n", level)
+	for _, c := range offending {
+		fmt.Fprintf(errOut, "- [%s] %s\n", c.Impact, describeSchemaChange(c))
+	}
+	return fmt.Errorf("schema diff has %d change(s) at or above %s; review them or relax --fail-on", len(offending), level)
+}
+
+// describeSchemaChange renders a classified change as "Table.target: kind (detail)".
+func describeSchemaChange(c schema.SchemaChange) string {
+	subject := c.Table
+	if c.Target != "" {
+		subject += "." + c.Target
 	}
-	client := api.NewClient(rc.BaseURL.Value, rc.DatabaseID.Value, rc.APIKey.Value, rc.APISecret.Value)
+	line := subject + ": " + c.Kind
+	if c.Detail != "" {
+		line += " (" + c.Detail + ")"
+	}
+	return line
+}
 
-	var tables []string
-	if strings.TrimSpace(tablesCSV) != "" {
-		tables = strings.Split(tablesCSV, ",")
+// revisionPublishPayload turns a stored revision into an upsert body: revision metadata is dropped
+// so the API records a new revision instead of echoing the old one.
+func revisionPublishPayload(raw []byte) ([]byte, error) {
+	var payload map[string]any
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("decode schema revision: %w", err)
 	}
-	raw, rev, err := client.GetSchemaRaw(tables)
+	delete(payload, "meta")
+	encoded, err := json.Marshal(payload)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("encode schema revision: %w", err)
 	}
-	if rev == nil || rev.Meta == nil || rev.Meta.RevisionID == "" || rev.Meta.PublishedAt == "" {
-		warnMissingMeta(cmd, "get", rc)
+	body, err := normalizeSchemaForAPI(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("sanitize schema: %w", err)
 	}
+	return body, nil
+}
 
-	data := raw
-	if len(bytes.TrimSpace(data)) > 0 {
-		if sanitized, err := sanitizeSchemaJSON(data); err == nil {
-			data = sanitized
-		} else {
-			return fmt.Errorf("sanitize schema: %w", err)
-		}
-	} else if rev != nil {
-		pretty, err := json.MarshalIndent(rev, "", "  ")
-		if err != nil {
-			return fmt.Errorf("encode schema: %w", err)
+// confirmPrompt asks a yes/no question on the command's input. It refuses to guess when stdin is not
+// a terminal so scripts must opt in with --yes.
+func confirmPrompt(cmd *cobra.Command, question string) (bool, error) {
+	in := cmd.InOrStdin()
+	if f, ok := in.(*os.File); ok {
+		if info, err := f.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return false, fmt.Errorf("confirmation required but stdin is not a terminal; re-run with --yes to proceed")
 		}
-		data = pretty
 	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s [y/N]: ", question)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func revisionCreatedAt(rev schema.SchemaRevision) string {
+	if rev.Meta == nil {
+		return ""
+	}
+	return rev.Meta.CreatedAt
+}
+
+// printSchemaHistory renders revisions as an aligned table, or as JSON metadata when asJSON is set.
+func printSchemaHistory(cmd *cobra.Command, revs []schema.SchemaRevision, asJSON bool) error {
+	out := cmd.OutOrStdout()
+	if asJSON {
+		type historyEntry struct {
+			RevisionID  string   `json:"revisionId"`
+			CreatedAt   string   `json:"createdAt,omitempty"`
+			PublishedAt string   `json:"publishedAt,omitempty"`
+			Tables      []string `json:"tables"`
+		}
+		entries := make([]historyEntry, 0, len(revs))
+		for _, rev := range revs {
+			e := historyEntry{Tables: []string{}}
+			if rev.Meta != nil {
+				e.RevisionID, e.CreatedAt, e.PublishedAt = rev.Meta.RevisionID, rev.Meta.CreatedAt, rev.Meta.PublishedAt
+			}
+			for _, t := range rev.Tables {
+				e.Tables = append(e.Tables, t.Name)
+			}
+			entries = append(entries, e)
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode history: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+	if len(revs) == 0 {
+		fmt.Fprintln(out, "No schema revisions found.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REVISION\tCREATED\tPUBLISHED\tTABLES")
+	for _, rev := range revs {
+		id, created, published := "-", "-", "-"
+		if rev.Meta != nil {
+			id = firstNonBlank(rev.Meta.RevisionID, "-")
+			created = firstNonBlank(rev.Meta.CreatedAt, "-")
+			published = firstNonBlank(rev.Meta.PublishedAt, "-")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\n", id, created, published, len(rev.Tables))
+	}
+	return tw.Flush()
+}
+
+func firstNonBlank(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// schemaGetOptions carries the flags shared by `onyx schema` and `onyx schema get`.
+type schemaGetOptions struct {
+	OutPath   string
+	TablesCSV string
+	PrintOnly bool
+	Revision  string
+	Format    string
+	SplitDir  string
+}
+
+func runSchemaGet(cmd *cobra.Command, cfg *cfgOptions, opts schemaGetOptions, args []string) error {
+	if opts.SplitDir != "" && (opts.PrintOnly || opts.TablesCSV != "" || opts.OutPath != "" || len(args) > 0) {
+		return fmt.Errorf("--split cannot be combined with --print, --tables, --out or a file argument")
+	}
+	rc, err := resolveCfgOrGuide(cfg)
+	if err != nil {
+		return err
+	}
+	client := newAPIClient(cfg, rc)
 
-	// Pretty-print while preserving unknown fields by indenting the raw JSON.
+	var tables []string
+	if strings.TrimSpace(opts.TablesCSV) != "" {
+		tables = strings.Split(opts.TablesCSV, ",")
+	}
+	var raw []byte
+	var rev *schema.SchemaRevision
+	if strings.TrimSpace(opts.Revision) != "" {
+		if len(tables) > 0 {
+			return fmt.Errorf("--tables cannot be combined with --revision")
+		}
+		raw, rev, err = client.GetSchemaRevisionRawContext(cmd.Context(), opts.Revision)
+	} else {
+		raw, rev, err = client.GetSchemaRawContext(cmd.Context(), tables)
+	}
+	if err != nil {
+		return err
+	}
+	if rev == nil || rev.Meta == nil || rev.Meta.RevisionID == "" || rev.Meta.PublishedAt == "" {
+		warnMissingMeta(cmd, "get", rc)
+	}
+
+	data := raw
 	if len(bytes.TrimSpace(data)) > 0 {
-		var buf bytes.Buffer
-		if err := json.Indent(&buf, data, "", "  "); err == nil {
-			data = buf.Bytes()
+		if sanitized, err := sanitizeSchemaJSON(data); err == nil {
+			data = sanitized
+		} else {
+			return fmt.Errorf("sanitize schema: %w", err)
+		}
+		// canonical order keeps repeated pulls from producing noisy diffs
+		if data, err = schema.CanonicalizeSchema(data); err != nil {
+			return fmt.Errorf("format schema: %w", err)
+		}
+	} else if rev != nil {
+		pretty, err := json.MarshalIndent(rev, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode schema: %w", err)
 		}
+		data = pretty
 	}
-	data = append(data, '\n')
 
-	target := outPath
+	target := opts.OutPath
 	if target == "" && len(args) > 0 {
 		target = args[0]
 	}
+	format := schema.FileFormatJSON
+	if opts.Format != "" {
+		if format, err = schema.ParseFileFormat(opts.Format); err != nil {
+			return err
+		}
+	} else if target != "" {
+		format = schema.FileFormatForPath(target)
+	}
 	if target == "" {
-		target = config.DefaultSchemaPath
+		target = strings.TrimSuffix(config.DefaultSchemaPath, ".json") + "." + format
+	}
+	// the API never sees code generation hints, so keep those of the file being replaced
+	if !opts.PrintOnly && len(tables) == 0 && len(bytes.TrimSpace(data)) > 0 {
+		existing := target
+		if opts.SplitDir != "" {
+			existing = opts.SplitDir
+		}
+		if local, _, _, err := readSchemaFile(existing); err == nil {
+			if data, err = schema.RestoreLocalHints(data, local); err != nil {
+				return fmt.Errorf("restore local hints: %w", err)
+			}
+			if data, err = schema.CanonicalizeSchema(data); err != nil {
+				return fmt.Errorf("format schema: %w", err)
+			}
+		}
+	}
+	if opts.SplitDir != "" {
+		return writeSplitSchema(cmd, opts.SplitDir, data, format)
+	}
+
+	if format != schema.FileFormatJSON && len(bytes.TrimSpace(data)) > 0 {
+		converted, err := schema.EncodeSchemaFile(data, format)
+		if err != nil {
+			return err
+		}
+		data = converted
+	} else {
+		// Pretty-print while preserving unknown fields by indenting the raw JSON.
+		if len(bytes.TrimSpace(data)) > 0 {
+			var buf bytes.Buffer
+			if err := json.Indent(&buf, data, "", "  "); err == nil {
+				data = buf.Bytes()
+			}
+		}
+		data = append(data, '\n')
 	}
 
 	// Print to stdout when requested or when fetching specific tables (parity with TS CLI).
-	if printOnly || len(tables) > 0 {
+	if opts.PrintOnly || len(tables) > 0 {
 		fmt.Fprint(cmd.OutOrStdout(), string(data))
 		return nil
 	}
@@ -553,11 +1370,173 @@ func runSchemaGet(cmd *cobra.Command, cfg *cfgOptions, outPath, tablesCSV string
 	return nil
 }
 
+// runSchemaFmt canonicalizes a schema file, or a split schema's manifest and every included
+// table file, in place. With check set nothing is written and unformatted files are an error.
+func runSchemaFmt(cmd *cobra.Command, path string, check bool) error {
+	_, manifest, sources, err := readSchemaFile(path)
+	if err != nil {
+		return err
+	}
+	files := []string{manifest}
+	seen := map[string]bool{manifest: true}
+	for _, src := range sources {
+		file := filepath.Join(filepath.Dir(manifest), filepath.FromSlash(src.File))
+		if src.File != "" && !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+
+	// the TOML decoder drops comments and key order, so TOML files are only ever checked
+	if !check {
+		for _, file := range files {
+			if schema.FileFormatForPath(file) == schema.FileFormatTOML {
+				return fmt.Errorf("cannot format %s: rewriting TOML would lose its comments; use onyx schema fmt --check, or convert the schema to JSON or YAML", file)
+			}
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	var unformatted int
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("read schema %s: %w", file, err)
+		}
+		var canonical []byte
+		switch schema.FileFormatForPath(file) {
+		case schema.FileFormatTOML:
+			decoded, err := schema.DecodeSchemaFile(data, schema.FileFormatTOML)
+			if err != nil {
+				return fmt.Errorf("parse schema %s: %w", file, err)
+			}
+			ok, err := schema.IsCanonicalSchema(decoded)
+			if err != nil {
+				return fmt.Errorf("format schema %s: %w", file, err)
+			}
+			if ok {
+				continue
+			}
+		case schema.FileFormatYAML:
+			if canonical, err = schema.CanonicalizeSchemaYAML(data); err != nil {
+				return fmt.Errorf("format schema %s: %w", file, err)
+			}
+		default:
+			if canonical, err = schema.CanonicalizeSchema(data); err != nil {
+				return fmt.Errorf("format schema %s: %w", file, err)
+			}
+			if canonical, err = schema.EncodeSchemaFile(canonical, schema.FileFormatJSON); err != nil {
+				return fmt.Errorf("format schema %s: %w", file, err)
+			}
+		}
+		if bytes.Equal(canonical, data) {
+			continue
+		}
+		unformatted++
+		if check {
+			fmt.Fprintf(out, "%s is not formatted\n", file)
+			continue
+		}
+		if err := os.WriteFile(file, canonical, 0o644); err != nil {
+			return fmt.Errorf("write schema %s: %w", file, err)
+		}
+		fmt.Fprintf(out, "Formatted %s\n", file)
+	}
+	switch {
+	case unformatted == 0:
+		fmt.Fprintf(out, "%s is already formatted.\n", manifest)
+	case check:
+		return fmt.Errorf("%d schema file(s) need formatting; run onyx schema fmt", unformatted)
+	}
+	return nil
+}
+
+// writeSplitSchema writes the schema JSON as a manifest plus one file per table under dir,
+// removing table files and manifests left over from an earlier split.
+func writeSplitSchema(cmd *cobra.Command, dir string, data []byte, format string) error {
+	files, err := schema.SplitSchema(data, format)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "tables"), 0o755); err != nil {
+		return fmt.Errorf("create directory %s: %w", dir, err)
+	}
+	keep := map[string]bool{}
+	for _, f := range files {
+		keep[filepath.Join(dir, filepath.FromSlash(f.Path))] = true
+	}
+	stale, _ := filepath.Glob(filepath.Join(dir, "tables", "*"))
+	for _, name := range schema.ManifestNames {
+		stale = append(stale, filepath.Join(dir, name))
+	}
+	for _, path := range stale {
+		switch filepath.Ext(path) {
+		case ".json", ".yaml", ".yml", ".toml":
+			if !keep[path] {
+				if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("remove stale schema file %s: %w", path, err)
+				}
+			}
+		}
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, filepath.FromSlash(f.Path))
+		if err := os.WriteFile(path, f.Data, 0o644); err != nil {
+			return fmt.Errorf("write schema %s: %w", path, err)
+		}
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Wrote schema to %s (%d table file(s) under %s)\n", filepath.Join(dir, files[0].Path), len(files)-1, filepath.Join(dir, "tables"))
+	return nil
+}
+
 func loadLocalSchema(path string) (schema.SchemaUpsertRequest, string, []byte, error) {
+	data, file, _, err := readSchemaFile(path)
+	if err != nil {
+		return schema.SchemaUpsertRequest{}, path, nil, err
+	}
+	return parseSchemaJSON(data, file)
+}
+
+// readSchemaFile reads a schema file as JSON; path picks the format (JSON, YAML or TOML by
+// extension). A directory is read through its manifest (see schema.FindManifest), and manifest
+// includes are resolved relative to the manifest. It returns the file actually read and, for
+// included tables, where each was defined. Missing files match fs.ErrNotExist.
+func readSchemaFile(path string) ([]byte, string, []schema.TableSource, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		if path, err = schema.FindManifest(path); err != nil {
+			return nil, path, nil, err
+		}
+	}
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return schema.SchemaUpsertRequest{}, path, nil, fmt.Errorf("read schema %s: %w", path, err)
+		return nil, path, nil, fmt.Errorf("read schema %s: %w", path, err)
+	}
+	if data, err = schema.DecodeSchemaFile(data, schema.FileFormatForPath(path)); err != nil {
+		return nil, path, nil, fmt.Errorf("parse schema %s: %w", path, err)
+	}
+	data, sources, err := schema.ResolveIncludes(data, os.DirFS(filepath.Dir(path)))
+	if err != nil {
+		return nil, path, nil, fmt.Errorf("parse schema %s: %w", path, err)
 	}
+	return data, path, sources, nil
+}
+
+// parseLocalSchema normalizes schema file contents; path picks the format (JSON, YAML or TOML
+// by extension) and is used for messages. Manifests with includes are rejected since there is
+// no directory to resolve them against.
+func parseLocalSchema(data []byte, path string) (schema.SchemaUpsertRequest, string, []byte, error) {
+	data, err := schema.DecodeSchemaFile(data, schema.FileFormatForPath(path))
+	if err != nil {
+		return schema.SchemaUpsertRequest{}, path, nil, fmt.Errorf("parse schema %s: %w", path, err)
+	}
+	if data, _, err = schema.ResolveIncludes(data, nil); err != nil {
+		return schema.SchemaUpsertRequest{}, path, nil, fmt.Errorf("parse schema %s: %w", path, err)
+	}
+	return parseSchemaJSON(data, path)
+}
+
+// parseSchemaJSON normalizes decoded schema JSON into an upsert request; path is used for messages.
+func parseSchemaJSON(data []byte, path string) (schema.SchemaUpsertRequest, string, []byte, error) {
 	apiReady, err := normalizeSchemaForAPI(data)
 	if err != nil {
 		return schema.SchemaUpsertRequest{}, path, nil, err
@@ -569,16 +1548,120 @@ func loadLocalSchema(path string) (schema.SchemaUpsertRequest, string, []byte, e
 	return req, path, apiReady, nil
 }
 
+// schemaSource is one side of a schema comparison.
+type schemaSource struct {
+	Schema schema.SchemaUpsertRequest
+	Label  string // human-readable name used in diff headers
+	File   string // local file path, when the source is a file
+}
+
+// loadSchemaSource resolves a source spec: a file path, "api", "db:<databaseId>", "rev:<revisionId>", or "git:<ref>:<path>".
+// tables limits API sources to specific tables.
+func loadSchemaSource(ctx context.Context, cfg *cfgOptions, spec string, tables []string) (schemaSource, error) {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case spec == "api":
+		rc, err := resolveCfgOrGuide(cfg)
+		if err != nil {
+			return schemaSource{}, err
+		}
+		req, err := fetchSchemaSource(ctx, newAPIClient(cfg, rc), tables)
+		return schemaSource{Schema: req, Label: "API schema"}, err
+	case strings.HasPrefix(spec, "db:"):
+		dbID := strings.TrimSpace(strings.TrimPrefix(spec, "db:"))
+		if dbID == "" {
+			return schemaSource{}, fmt.Errorf("invalid schema source %q (expected db:<databaseId>)", spec)
+		}
+		rc, err := resolveCfgOrGuide(cfg)
+		if err != nil {
+			return schemaSource{}, err
+		}
+		rc.DatabaseID = config.ResolvedValue{Value: dbID, Source: "argument"}
+		req, err := fetchSchemaSource(ctx, newAPIClient(cfg, rc), tables)
+		return schemaSource{Schema: req, Label: "API schema (database " + dbID + ")"}, err
+	case strings.HasPrefix(spec, "rev:"):
+		revID := strings.TrimSpace(strings.TrimPrefix(spec, "rev:"))
+		if revID == "" {
+			return schemaSource{}, fmt.Errorf("invalid schema source %q (expected rev:<revisionId>)", spec)
+		}
+		rc, err := resolveCfgOrGuide(cfg)
+		if err != nil {
+			return schemaSource{}, err
+		}
+		client := newAPIClient(cfg, rc)
+		rev, err := client.GetSchemaRevisionContext(ctx, revID)
+		if err != nil {
+			return schemaSource{}, err
+		}
+		req := schema.SchemaUpsertRequest{DatabaseID: rev.DatabaseID, Tables: rev.Tables, Meta: rev.Meta}
+		return schemaSource{Schema: req, Label: "revision " + revID}, nil
+	case strings.HasPrefix(spec, "git:"):
+		ref, path, ok := strings.Cut(strings.TrimPrefix(spec, "git:"), ":")
+		if !ok || ref == "" || path == "" {
+			return schemaSource{}, fmt.Errorf("invalid schema source %q (expected git:<ref>:<path>, e.g. git:main:api/onyx.schema.json)", spec)
+		}
+		data, err := gitShowFile(ref, path)
+		if err != nil {
+			return schemaSource{}, err
+		}
+		req, _, _, err := parseLocalSchema(data, spec)
+		return schemaSource{Schema: req, Label: path + " @ " + ref}, err
+	default:
+		req, filePath, _, err := loadLocalSchema(spec)
+		return schemaSource{Schema: req, Label: filePath, File: filePath}, err
+	}
+}
+
+// fetchSchemaSource fetches the latest schema for the client's database.
+func fetchSchemaSource(ctx context.Context, client *api.Client, tables []string) (schema.SchemaUpsertRequest, error) {
+	rev, err := client.GetSchemaContext(ctx, tables)
+	if err != nil {
+		return schema.SchemaUpsertRequest{}, err
+	}
+	return schema.SchemaUpsertRequest{DatabaseID: rev.DatabaseID, Tables: rev.Tables, Meta: rev.Meta}, nil
+}
+
+// gitShowFile reads path as of ref via `git show`. Paths starting with ./ are relative to the working directory.
+func gitShowFile(ref, path string) ([]byte, error) {
+	out, err := exec.Command("git", "show", ref+":"+filepath.ToSlash(path)).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git show %s:%s: %s", ref, path, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git show %s:%s: %w (is git installed and is this a git repository?)", ref, path, err)
+	}
+	return out, nil
+}
+
+// schemaTablesKey reports which top-level key holds tables in the file ("entities" for legacy files).
+func schemaTablesKey(path string) string {
+	data, _, _, err := readSchemaFile(path)
+	if err != nil {
+		return "tables"
+	}
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(data, &top); err != nil {
+		return "tables"
+	}
+	if _, ok := top["tables"]; !ok {
+		if _, ok := top["entities"]; ok {
+			return "entities"
+		}
+	}
+	return "tables"
+}
+
 // pickSchemaPath chooses the schema path from a flag or optional positional arg.
 // Positional arg wins, then flag, then default.
 func pickSchemaPath(flagPath string, args []string) string {
 	if len(args) > 0 && strings.TrimSpace(args[0]) != "" {
 		return args[0]
 	}
-	if flagPath != "" {
+	if flagPath != "" && flagPath != config.DefaultSchemaPath {
 		return flagPath
 	}
-	return config.DefaultSchemaPath
+	return existingSchemaVariant(config.DefaultSchemaPath)
 }
 
 func warnMissingMeta(cmd *cobra.Command, action string, rc config.ResolvedConfig) {
@@ -617,7 +1700,8 @@ func stripEntityText(v any) {
 	}
 }
 
-// normalizeSchemaForAPI ensures payload uses tables (and entities for compatibility) and strips entityText.
+// normalizeSchemaForAPI ensures payload uses tables (and entities for compatibility) and strips
+// entityText and the local-only code generation hints.
 func normalizeSchemaForAPI(raw []byte) ([]byte, error) {
 	if len(bytes.TrimSpace(raw)) == 0 {
 		return raw, nil
@@ -627,6 +1711,7 @@ func normalizeSchemaForAPI(raw []byte) ([]byte, error) {
 		return nil, fmt.Errorf("decode schema json: %w", err)
 	}
 	stripEntityText(v)
+	schema.StripLocalHints(v)
 	ensureTablesKey(v)
 	ensureEntitiesFromTables(v)
 	out, err := json.Marshal(v)
@@ -677,6 +1762,450 @@ func boolValue(ptr *bool) bool {
 	return *ptr
 }
 
+// AUTH ---------------------------------------------------------------------
+func newAuthCmd(cfg *cfgOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manage API credentials in the local encrypted credential store",
+	}
+	cmd.AddCommand(newAuthLoginCmd(cfg), newAuthLogoutCmd(cfg), newAuthStatusCmd(cfg))
+	return cmd
+}
+
+func newAuthLoginCmd(cfg *cfgOptions) *cobra.Command {
+	var secretStdin, skipVerify bool
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Store an API key/secret for a database in ~/.onyx (encrypted)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rc, err := resolveCfg(cfg)
+			if err != nil {
+				return err
+			}
+			in := bufio.NewReader(cmd.InOrStdin())
+			cred := config.Credential{
+				DatabaseID: firstNonBlank(cfg.DatabaseID, rc.DatabaseID.Value),
+				APIKey:     cfg.APIKey,
+				APISecret:  cfg.APISecret,
+			}
+			if rc.BaseURL.Source != "default" {
+				cred.BaseURL = rc.BaseURL.Value
+			}
+			if secretStdin {
+				line, err := in.ReadString('\n')
+				if err != nil && line == "" {
+					return fmt.Errorf("read API secret from stdin: %w", err)
+				}
+				cred.APISecret = strings.TrimSpace(line)
+			}
+			for _, field := range []struct {
+				label string
+				value *string
+			}{
+				{"Database ID", &cred.DatabaseID},
+				{"API key", &cred.APIKey},
+				{"API secret", &cred.APISecret},
+			} {
+				if strings.TrimSpace(*field.value) != "" {
+					continue
+				}
+				if *field.value, err = promptLine(cmd, in, field.label); err != nil {
+					return err
+				}
+			}
+
+			if !skipVerify {
+				verifyRC := rc
+				verifyRC.DatabaseID.Value, verifyRC.APIKey.Value, verifyRC.APISecret.Value = cred.DatabaseID, cred.APIKey, cred.APISecret
+				if _, err := newAPIClient(cfg, verifyRC).GetSchemaContext(cmd.Context(), nil); err != nil {
+					return fmt.Errorf("verify credentials (use --skip-verify to store them anyway): %w", err)
+				}
+			}
+			store := config.DefaultCredentialStore()
+			if err := store.Put(cred); err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Stored credentials for database %s in %s\n", cred.DatabaseID, store.Path)
+			if strings.HasPrefix(rc.APISecret.Source, "config:") {
+				fmt.Fprintf(out, "Note: apiSecret is still set in %s and takes precedence; remove it to use the stored secret.\n", strings.TrimPrefix(rc.APISecret.Source, "config:"))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&secretStdin, "api-secret-stdin", false, "Read the API secret from the first line of stdin")
+	cmd.Flags().BoolVar(&skipVerify, "skip-verify", false, "Store credentials without checking them against the Schema API")
+	return cmd
+}
+
+func newAuthLogoutCmd(cfg *cfgOptions) *cobra.Command {
+	var all bool
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Remove stored credentials for a database (or --all)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store := config.DefaultCredentialStore()
+			var ids []string
+			if all {
+				creds, err := store.List()
+				if err != nil {
+					return err
+				}
+				for _, c := range creds {
+					ids = append(ids, c.DatabaseID)
+				}
+			} else {
+				dbID := cfg.DatabaseID
+				if dbID == "" {
+					rc, err := resolveCfg(cfg)
+					if err != nil {
+						return err
+					}
+					dbID = rc.DatabaseID.Value
+				}
+				if dbID == "" {
+					return fmt.Errorf("no database ID resolved; pass --database-id or --all")
+				}
+				ids = []string{dbID}
+			}
+			out := cmd.OutOrStdout()
+			for _, id := range ids {
+				removed, err := store.Delete(id)
+				if err != nil {
+					return err
+				}
+				if removed {
+					fmt.Fprintf(out, "Removed stored credentials for database %s\n", id)
+				} else {
+					fmt.Fprintf(out, "No stored credentials for database %s\n", id)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&all, "all", false, "Remove every stored credential")
+	return cmd
+}
+
+func newAuthStatusCmd(cfg *cfgOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "List stored credentials and where the active secret comes from",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store := config.DefaultCredentialStore()
+			creds, err := store.List()
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Credential store: %s\n", store.Path)
+			if len(creds) == 0 {
+				fmt.Fprintln(out, "No stored credentials (run onyx auth login).")
+			} else {
+				tw := tabwriter.NewWriter(out, 0, 2, 2, ' ', 0)
+				fmt.Fprintln(tw, "DATABASE\tAPI KEY\tAPI SECRET\tBASE URL")
+				for _, c := range creds {
+					fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.DatabaseID, c.APIKey, config.MaskSecret(c.APISecret), firstNonBlank(c.BaseURL, "-"))
+				}
+				if err := tw.Flush(); err != nil {
+					return err
+				}
+			}
+			rc, err := resolveCfg(cfg)
+			if err == nil {
+				err = rc.FillCredentials()
+			}
+			if err != nil {
+				fmt.Fprintf(out, "Active credentials: unresolved (%v)\n", err)
+				return nil
+			}
+			if rc.CredentialHelper.Value != "" {
+				fmt.Fprintf(out, "credentialHelper: %s (source: %s)\n", rc.CredentialHelper.Value, rc.CredentialHelper.Source)
+			}
+			if rc.DatabaseID.Value == "" {
+				fmt.Fprintln(out, "Active credentials: none (no database ID resolved)")
+				return nil
+			}
+			if rc.APISecret.Value == "" {
+				fmt.Fprintf(out, "Active credentials: none for database %s\n", rc.DatabaseID.Value)
+				return nil
+			}
+			fmt.Fprintf(out, "Active credentials: database %s, apiSecret from %s\n", rc.DatabaseID.Value, rc.APISecret.Source)
+			return nil
+		},
+	}
+}
+
+// promptLine asks for a value on a terminal; it errors instead of blocking when stdin is not one.
+func promptLine(cmd *cobra.Command, in *bufio.Reader, label string) (string, error) {
+	if f, ok := cmd.InOrStdin().(*os.File); ok {
+		if info, err := f.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return "", fmt.Errorf("%s is required; pass it as a flag when stdin is not a terminal", strings.ToLower(label))
+		}
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s: ", label)
+	line, err := in.ReadString('\n')
+	line = strings.TrimSpace(line)
+	if line == "" {
+		if err == nil {
+			err = fmt.Errorf("%s is required", strings.ToLower(label))
+		}
+		return "", err
+	}
+	return line, nil
+}
+
+// CONFIG -------------------------------------------------------------------
+func newConfigCmd(cfg *cfgOptions) *cobra.Command {
+	var scope string
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Read and edit onyx-database.json config files",
+		Long: "Scopes: project (./onyx-database.json), config (./config/onyx-database.json), user (~/.onyx/onyx-database.json).\n" +
+			"--config targets an explicit file instead; --profile edits that profile's entry in the file.\n" +
+			"Without --scope or --config, get/list show resolved values and path shows the file in use.",
+	}
+	cmd.PersistentFlags().StringVar(&scope, "scope", "", "Config file scope: project, config or user")
+
+	valueFor := func(key, value string) string {
+		if config.IsSecretKey(key) {
+			return config.MaskSecret(value)
+		}
+		return value
+	}
+
+	pathCmd := &cobra.Command{
+		Use:   "path",
+		Short: "Print the config file for --scope, or the one currently in use",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			path, err := configTargetPath(cfg, scope, "")
+			if err != nil {
+				return err
+			}
+			if path != "" {
+				fmt.Fprintln(out, path)
+				return nil
+			}
+			rc, err := resolveCfg(cfg)
+			if err != nil {
+				return err
+			}
+			if rc.ConfigFile != "" {
+				fmt.Fprintln(out, rc.ConfigFile)
+				return nil
+			}
+			wd, _ := os.Getwd()
+			fmt.Fprintln(cmd.ErrOrStderr(), "No config file found; searched:")
+			for _, p := range config.SearchPaths(wd, rc.DatabaseID.Value) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "  %s\n", p)
+			}
+			return fmt.Errorf("no config file found")
+		},
+	}
+
+	getCmd := &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print one config value (secrets masked)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := args[0]
+			if err := config.CheckKey(key); err != nil {
+				return err
+			}
+			path, err := configTargetPath(cfg, scope, "")
+			if err != nil {
+				return err
+			}
+			var value string
+			if path != "" {
+				values, err := config.ReadFileValues(path, cfg.Profile)
+				if err != nil {
+					return err
+				}
+				var ok bool
+				if value, ok = values[key]; !ok {
+					return fmt.Errorf("%s is not set in %s", key, path)
+				}
+			} else {
+				rc, err := resolveCfg(cfg)
+				if err != nil {
+					return err
+				}
+				rv, _ := rc.Lookup(key)
+				if rv.Value == "" {
+					return fmt.Errorf("%s is not set", key)
+				}
+				value = rv.Value
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), valueFor(key, value))
+			return nil
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List config values with their sources (secrets masked)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := configTargetPath(cfg, scope, "")
+			if err != nil {
+				return err
+			}
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 2, 2, ' ', 0)
+			if path != "" {
+				values, err := config.ReadFileValues(path, cfg.Profile)
+				if err != nil {
+					return err
+				}
+				for _, key := range config.Keys {
+					if v, ok := values[key]; ok {
+						fmt.Fprintf(tw, "%s\t%s\n", key, valueFor(key, v))
+					}
+				}
+			} else {
+				rc, err := resolveCfg(cfg)
+				if err != nil {
+					return err
+				}
+				for _, key := range config.Keys {
+					if rv, _ := rc.Lookup(key); rv.Value != "" {
+						fmt.Fprintf(tw, "%s\t%s\t(source: %s)\n", key, valueFor(key, rv.Value), rv.Source)
+					}
+				}
+			}
+			return tw.Flush()
+		},
+	}
+
+	setCmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a value in the config file for --scope (default project)",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := configTargetPath(cfg, scope, config.ScopeProject)
+			if err != nil {
+				return err
+			}
+			if err := config.SetFileValue(path, cfg.Profile, args[0], args[1]); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Set %s in %s\n", configKeyLabel(args[0], cfg.Profile), path)
+			return nil
+		},
+	}
+
+	unsetCmd := &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Remove a value from the config file for --scope (default project)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := configTargetPath(cfg, scope, config.ScopeProject)
+			if err != nil {
+				return err
+			}
+			removed, err := config.UnsetFileValue(path, cfg.Profile, args[0])
+			if err != nil {
+				return err
+			}
+			if removed {
+				fmt.Fprintf(cmd.OutOrStdout(), "Removed %s from %s\n", configKeyLabel(args[0], cfg.Profile), path)
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s is not set in %s\n", configKeyLabel(args[0], cfg.Profile), path)
+			}
+			return nil
+		},
+	}
+
+	cmd.AddCommand(pathCmd, getCmd, listCmd, setCmd, unsetCmd)
+	return cmd
+}
+
+// configTargetPath picks the file `onyx config` works on: --scope, then --config, then fallback
+// (empty fallback means "use resolved values").
+func configTargetPath(cfg *cfgOptions, scope string, fallback config.Scope) (string, error) {
+	if scope == "" && cfg.ConfigPath != "" {
+		return cfg.ConfigPath, nil
+	}
+	if scope == "" {
+		scope = string(fallback)
+	}
+	if scope == "" {
+		return "", nil
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return config.ScopePath(config.Scope(scope), wd)
+}
+
+func configKeyLabel(key, profile string) string {
+	if profile == "" {
+		return key
+	}
+	return "profiles." + profile + "." + key
+}
+
+// DEV ----------------------------------------------------------------------
+func newDevCmd(cfg *cfgOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dev",
+		Short: "Local development helpers",
+	}
+	cmd.AddCommand(newDevServerCmd(cfg))
+	return cmd
+}
+
+func newDevServerCmd(cfg *cfgOptions) *cobra.Command {
+	var addr, dataDir, seedPath string
+	cmd := &cobra.Command{
+		Use:   "server",
+		Short: "Run a local mock of the Onyx Schema API (revisions stored on disk)",
+		Long: "Serves GET/PUT /schemas/{databaseId}, POST /schemas/{databaseId}/validate, GET /schemas/history/{databaseId}\n" +
+			"and GET /schemas/history/{databaseId}/{revisionId}, plus PUT /data/{databaseId}/query/{table} for gen --infer-embedded\n" +
+			"(records come from <data-dir>/<databaseId>/records/<table>.json), so get/diff/validate/publish/history/rollback\n" +
+			"work without network access.\n" +
+			"--api-key/--api-secret, when given, are required from clients; --database-id names the database --seed publishes into.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			srv := devserver.New(devserver.Options{Dir: dataDir, APIKey: cfg.APIKey, APISecret: cfg.APISecret})
+			dbID := firstNonBlank(cfg.DatabaseID, "dev")
+			if seedPath != "" {
+				req, _, _, err := loadLocalSchema(seedPath)
+				if err != nil {
+					return err
+				}
+				if err := srv.Seed(dbID, req); err != nil {
+					return fmt.Errorf("seed %s: %w", seedPath, err)
+				}
+			}
+			ln, err := net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Onyx dev server listening on http://%s (data: %s)\n", ln.Addr(), dataDir)
+			fmt.Fprintf(out, "Point the CLI at it with:\n  export ONYX_DATABASE_BASE_URL=http://%s ONYX_DATABASE_ID=%s ONYX_DATABASE_API_KEY=%s ONYX_DATABASE_API_SECRET=%s\n",
+				ln.Addr(), dbID, firstNonBlank(cfg.APIKey, "dev"), firstNonBlank(cfg.APISecret, "dev"))
+
+			httpSrv := &http.Server{Handler: srv}
+			go func() {
+				<-cmd.Context().Done()
+				_ = httpSrv.Close()
+			}()
+			if err := httpSrv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&addr, "addr", "127.0.0.1:8787", "Listen address")
+	cmd.Flags().StringVar(&dataDir, "data-dir", ".onyx/dev-server", "Directory for stored schema revisions")
+	cmd.Flags().StringVar(&seedPath, "seed", "", "Schema file published as the first revision when the database has none")
+	return cmd
+}
+
 // VERSION ------------------------------------------------------------------
 func newVersionCmd() *cobra.Command {
 	return &cobra.Command{
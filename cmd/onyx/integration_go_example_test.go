@@ -12,6 +12,9 @@ func TestExampleGoE2E(t *testing.T) {
 	if os.Getenv("ONYX_E2E") == "" {
 		t.Skip("skipped: ONYX_E2E not set (set ONYX_E2E=1 to run integration test)")
 	}
+	if os.Getenv("ONYX_E2E") == "local" {
+		t.Skip("skipped: example SDKs need a live data API (ONYX_E2E=local only mocks the Schema API)")
+	}
 
 	repo := moduleRoot(t)
 	cfgPath := filepath.Join(repo, "onyx-database.json")
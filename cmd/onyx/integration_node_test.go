@@ -13,6 +13,9 @@ func TestExampleNodeE2E(t *testing.T) {
 	if os.Getenv("ONYX_E2E") == "" {
 		t.Skip("skipped: ONYX_E2E not set (set ONYX_E2E=1 to run integration test)")
 	}
+	if os.Getenv("ONYX_E2E") == "local" {
+		t.Skip("skipped: example SDKs need a live data API (ONYX_E2E=local only mocks the Schema API)")
+	}
 
 	// Populate env vars from root onyx-database.json if present and envs missing.
 	repo := moduleRoot(t)
@@ -4,16 +4,20 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+
+	"github.com/OnyxDevTools/onyx-cli/internal/devserver"
 )
 
 // Integration tests that exercise the CLI against a live Onyx service.
-// Opt-in: set ONYX_E2E=1 to run.
+// Opt-in: set ONYX_E2E=1 to run, or ONYX_E2E=local to run against an in-process
+// dev server seeded from the repo's onyx.schema.json (no network or credentials needed).
 func TestVersion(t *testing.T) {
 	workdir := prepareE2EWorkspace(t)
 	out, _ := runCLI(t, workdir, "version")
@@ -23,7 +27,7 @@ func TestVersion(t *testing.T) {
 func TestInfo(t *testing.T) {
 	workdir := prepareE2EWorkspace(t)
 	out, _ := runCLI(t, workdir, "info")
-	assertOutputContains(t, out, "databaseId:", "baseUrl:", "apiKey:")
+	assertOutputContains(t, out, "databaseId:", "baseUrl:", "apiKey:", "config search", "connection:   ok")
 }
 
 func TestInit(t *testing.T) {
@@ -89,6 +93,27 @@ func TestGenTS(t *testing.T) {
 	assertOutputContains(t, out, "TypeScript types ->")
 }
 
+func TestGenTSTablesFromFile(t *testing.T) {
+	workdir := prepareE2EWorkspace(t)
+	schemaPath := ensureSchema(t, workdir)
+	tableNames := loadTableNames(t, schemaPath)
+	if len(tableNames) < 2 {
+		t.Skip("schema needs at least two tables to check --tables filtering")
+	}
+	outPath := filepath.Join(workdir, "gen-ts-tables", "types.ts")
+	runCLI(t, workdir, "gen", "--ts", "--source", "file", "--schema", schemaPath, "--tables", tableNames[0], "--out", outPath)
+	assertFileContains(t, outPath, "export interface "+tableNames[0]+" ")
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read %s: %v", outPath, err)
+	}
+	for _, name := range tableNames[1:] {
+		if strings.Contains(string(data), "export interface "+name+" ") {
+			t.Fatalf("--tables %s still generated %s:\n%s", tableNames[0], name, data)
+		}
+	}
+}
+
 func TestGenPy(t *testing.T) {
 	workdir := prepareE2EWorkspace(t)
 	schemaPath := ensureSchema(t, workdir)
@@ -136,6 +161,9 @@ func prepareE2EWorkspace(t *testing.T) string {
 	}
 
 	repoRoot := moduleRoot(t)
+	if os.Getenv("ONYX_E2E") == "local" {
+		return prepareLocalE2EWorkspace(t, repoRoot)
+	}
 
 	// Require local config file so we don't bake secrets into the test.
 	rootCfg := filepath.Join(repoRoot, "onyx-database.json")
@@ -166,6 +194,36 @@ func prepareE2EWorkspace(t *testing.T) string {
 	return workdir
 }
 
+// prepareLocalE2EWorkspace starts a dev server seeded with onyx.schema.json and points
+// ONYX_CONFIG_PATH at a config for it. Revisions live in a temp dir, so each test starts fresh.
+func prepareLocalE2EWorkspace(t *testing.T, repoRoot string) string {
+	t.Helper()
+	seed, _, _, err := loadLocalSchema(filepath.Join(repoRoot, "onyx.schema.json"))
+	if err != nil {
+		t.Fatalf("load seed schema: %v", err)
+	}
+	srv := devserver.New(devserver.Options{Dir: t.TempDir(), APIKey: "local-key", APISecret: "local-secret"})
+	if err := srv.Seed("local-db", seed); err != nil {
+		t.Fatalf("seed dev server: %v", err)
+	}
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+
+	workdir := t.TempDir()
+	cfg, _ := json.Marshal(map[string]string{
+		"databaseId": "local-db",
+		"baseUrl":    ts.URL,
+		"apiKey":     "local-key",
+		"apiSecret":  "local-secret",
+	})
+	cfgPath := filepath.Join(workdir, "onyx-database.json")
+	if err := os.WriteFile(cfgPath, cfg, 0o600); err != nil {
+		t.Fatalf("write local config: %v", err)
+	}
+	t.Setenv("ONYX_CONFIG_PATH", cfgPath)
+	return workdir
+}
+
 func runCLI(t *testing.T, workdir string, args ...string) (string, string) {
 	t.Helper()
 	cmd := newRootCmd()
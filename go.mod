@@ -3,6 +3,7 @@ module github.com/OnyxDevTools/onyx-cli
 go 1.24.0
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/OnyxDevTools/onyx-database-go v0.2.0
 	github.com/spf13/cobra v1.8.1
 	gopkg.in/yaml.v3 v3.0.1
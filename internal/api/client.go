@@ -2,7 +2,9 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -20,16 +22,51 @@ type Client struct {
 	apiKey     string
 	apiSecret  string
 	http       *http.Client
+	retry      RetryPolicy
 }
 
-func NewClient(baseURL, databaseID, apiKey, apiSecret string) *Client {
-	return &Client{
+// DefaultTimeout bounds a single HTTP attempt (retries get their own budget).
+const DefaultTimeout = 30 * time.Second
+
+// Option customizes a Client.
+type Option func(*Client)
+
+// WithTimeout sets the per-attempt HTTP timeout. Zero or negative keeps the default.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		if d > 0 {
+			c.http.Timeout = d
+		}
+	}
+}
+
+// WithRetryPolicy overrides the retry/backoff policy.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// WithHTTPClient swaps the underlying HTTP client (its Timeout is used as-is).
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *Client) {
+		if h != nil {
+			c.http = h
+		}
+	}
+}
+
+func NewClient(baseURL, databaseID, apiKey, apiSecret string, opts ...Option) *Client {
+	c := &Client{
 		baseURL:    strings.TrimRight(baseURL, "/"),
 		databaseID: databaseID,
 		apiKey:     apiKey,
 		apiSecret:  apiSecret,
-		http:       &http.Client{Timeout: 30 * time.Second},
+		http:       &http.Client{Timeout: DefaultTimeout},
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 func (c *Client) headers() http.Header {
@@ -41,45 +78,75 @@ func (c *Client) headers() http.Header {
 	return h
 }
 
-func (c *Client) request(method, path string, body any) ([]byte, error) {
-	var reader io.Reader
+func (c *Client) request(ctx context.Context, method, path string, body any) ([]byte, error) {
+	var buf []byte
 	if body != nil {
-		buf, err := json.Marshal(body)
+		var err error
+		buf, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("encode request: %w", err)
 		}
-		reader = bytes.NewReader(buf)
 	}
-	return c.requestRaw(method, path, reader)
+	return c.requestRaw(ctx, method, path, buf)
 }
 
-func (c *Client) requestRaw(method, path string, body io.Reader) ([]byte, error) {
-	req, err := http.NewRequest(method, c.baseURL+path, body)
+// requestRaw sends the request, retrying transient failures (429, 5xx, connection resets)
+// with exponential backoff and jitter. The body is replayed on each attempt.
+func (c *Client) requestRaw(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	return c.send(ctx, method, path, body, method == http.MethodGet)
+}
+
+// send is requestRaw with an explicit idempotency flag, for non-GET requests that only read.
+func (c *Client) send(ctx context.Context, method, path string, body []byte, idempotent bool) ([]byte, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for attempt := 0; ; attempt++ {
+		data, retryAfter, err := c.do(ctx, method, path, body)
+		if err == nil {
+			return data, nil
+		}
+		if attempt >= c.retry.MaxRetries || !isRetryable(ctx, idempotent, err, retryAfter) {
+			return nil, err
+		}
+		if err := sleepContext(ctx, c.retry.delay(attempt, retryAfter)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// do performs a single attempt. retryAfter is the server-requested wait, if any.
+func (c *Client) do(ctx context.Context, method, path string, body []byte) ([]byte, time.Duration, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	req.Header = c.headers()
 	res, err := c.http.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer res.Body.Close()
 	data, err := io.ReadAll(res.Body)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		return nil, fmt.Errorf("%s %s: %s", method, path, strings.TrimSpace(string(data)))
+		return nil, parseRetryAfter(res.Header.Get("Retry-After"), time.Now()), newAPIError(method, path, res, data)
 	}
-	return data, nil
+	return data, 0, nil
 }
 
-func (c *Client) GetSchema(tables []string) (*schema.SchemaRevision, error) {
-	_, rev, err := c.GetSchemaRaw(tables)
+func (c *Client) GetSchemaContext(ctx context.Context, tables []string) (*schema.SchemaRevision, error) {
+	_, rev, err := c.GetSchemaRawContext(ctx, tables)
 	return rev, err
 }
 
-func (c *Client) GetSchemaRaw(tables []string) ([]byte, *schema.SchemaRevision, error) {
+func (c *Client) GetSchemaRawContext(ctx context.Context, tables []string) ([]byte, *schema.SchemaRevision, error) {
 	var path string
 	if len(tables) > 0 {
 		trimmed := make([]string, 0, len(tables))
@@ -96,7 +163,7 @@ func (c *Client) GetSchemaRaw(tables []string) ([]byte, *schema.SchemaRevision,
 	if path == "" {
 		path = fmt.Sprintf("/schemas/%s", url.PathEscape(c.databaseID))
 	}
-	raw, err := c.requestRaw(http.MethodGet, path, nil)
+	raw, err := c.requestRaw(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -117,29 +184,105 @@ func (c *Client) GetSchemaRaw(tables []string) ([]byte, *schema.SchemaRevision,
 	return raw, &rev, nil
 }
 
-func (c *Client) ValidateSchema(req schema.SchemaUpsertRequest) (*schema.SchemaValidationResult, error) {
+// ListSchemaRevisionsContext returns the schema revision history for the database.
+func (c *Client) ListSchemaRevisionsContext(ctx context.Context) ([]schema.SchemaRevision, error) {
+	_, revs, err := c.listSchemaRevisionsRaw(ctx)
+	return revs, err
+}
+
+// GetSchemaRevisionContext fetches a specific revision from the history by its revision ID.
+func (c *Client) GetSchemaRevisionContext(ctx context.Context, revisionID string) (*schema.SchemaRevision, error) {
+	_, rev, err := c.GetSchemaRevisionRawContext(ctx, revisionID)
+	return rev, err
+}
+
+// GetSchemaRevisionRawContext returns the raw JSON of a specific revision alongside its decoded form.
+// It fetches the single revision from GET /schemas/history/{databaseId}/{revisionId} rather than the
+// whole history.
+func (c *Client) GetSchemaRevisionRawContext(ctx context.Context, revisionID string) ([]byte, *schema.SchemaRevision, error) {
+	revisionID = strings.TrimSpace(revisionID)
+	if revisionID == "" {
+		return nil, nil, fmt.Errorf("revision ID is required")
+	}
+	path := fmt.Sprintf("/schemas/history/%s/%s", url.PathEscape(c.databaseID), url.PathEscape(revisionID))
+	raw, err := c.requestRaw(ctx, http.MethodGet, path, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil, fmt.Errorf("schema revision %s not found for database %s (run onyx schema history to list revisions): %w", revisionID, c.databaseID, err)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	var rev schema.SchemaRevision
+	if err := json.Unmarshal(raw, &rev); err != nil {
+		return nil, nil, fmt.Errorf("decode schema revision: %w", err)
+	}
+	c.normalizeRevision(&rev)
+	return raw, &rev, nil
+}
+
+func (c *Client) listSchemaRevisionsRaw(ctx context.Context) ([]json.RawMessage, []schema.SchemaRevision, error) {
+	path := fmt.Sprintf("/schemas/history/%s", url.PathEscape(c.databaseID))
+	raw, err := c.requestRaw(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var entries []json.RawMessage
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		// Some deployments wrap the list: {"revisions": [...]}.
+		var wrapped struct {
+			Revisions []json.RawMessage `json:"revisions"`
+		}
+		if wrapErr := json.Unmarshal(raw, &wrapped); wrapErr != nil {
+			return nil, nil, fmt.Errorf("decode schema history: %w", err)
+		}
+		entries = wrapped.Revisions
+	}
+	revs := make([]schema.SchemaRevision, len(entries))
+	for i, entry := range entries {
+		if err := json.Unmarshal(entry, &revs[i]); err != nil {
+			return nil, nil, fmt.Errorf("decode schema revision: %w", err)
+		}
+		c.normalizeRevision(&revs[i])
+	}
+	return entries, revs, nil
+}
+
+// normalizeRevision mirrors entities/tables and fills in the client's database ID.
+func (c *Client) normalizeRevision(rev *schema.SchemaRevision) {
+	if len(rev.Tables) == 0 && len(rev.Entities) > 0 {
+		rev.Tables = rev.Entities
+	}
+	if len(rev.Entities) == 0 && len(rev.Tables) > 0 {
+		rev.Entities = rev.Tables
+	}
+	if rev.DatabaseID == "" {
+		rev.DatabaseID = c.databaseID
+	}
+}
+
+func (c *Client) ValidateSchemaContext(ctx context.Context, req schema.SchemaUpsertRequest) (*schema.SchemaValidationResult, error) {
 	body, err := ensureDatabaseIDRaw(req, c.databaseID)
 	if err != nil {
 		return nil, err
 	}
-	return c.ValidateSchemaRaw(body)
+	return c.ValidateSchemaRawContext(ctx, body)
 }
 
-func (c *Client) UpdateSchema(req schema.SchemaUpsertRequest, publish bool) (*schema.SchemaRevision, error) {
+func (c *Client) UpdateSchemaContext(ctx context.Context, req schema.SchemaUpsertRequest, publish bool) (*schema.SchemaRevision, error) {
 	body, err := ensureDatabaseIDRaw(req, c.databaseID)
 	if err != nil {
 		return nil, err
 	}
-	return c.UpdateSchemaRaw(body, publish)
+	return c.UpdateSchemaRawContext(ctx, body, publish)
 }
 
-func (c *Client) ValidateSchemaRaw(body []byte) (*schema.SchemaValidationResult, error) {
+func (c *Client) ValidateSchemaRawContext(ctx context.Context, body []byte) (*schema.SchemaValidationResult, error) {
 	body, err := ensureDatabaseIDRawBytes(body, c.databaseID)
 	if err != nil {
 		return nil, err
 	}
 	path := fmt.Sprintf("/schemas/%s/validate", url.PathEscape(c.databaseID))
-	raw, err := c.requestRaw(http.MethodPost, path, bytes.NewReader(body))
+	raw, err := c.requestRaw(ctx, http.MethodPost, path, body)
 	if err != nil {
 		return nil, err
 	}
@@ -154,7 +297,7 @@ func (c *Client) ValidateSchemaRaw(body []byte) (*schema.SchemaValidationResult,
 	return &res, nil
 }
 
-func (c *Client) UpdateSchemaRaw(body []byte, publish bool) (*schema.SchemaRevision, error) {
+func (c *Client) UpdateSchemaRawContext(ctx context.Context, body []byte, publish bool) (*schema.SchemaRevision, error) {
 	body, err := ensureDatabaseIDRawBytes(body, c.databaseID)
 	if err != nil {
 		return nil, err
@@ -167,7 +310,7 @@ func (c *Client) UpdateSchemaRaw(body []byte, publish bool) (*schema.SchemaRevis
 	if q := qs.Encode(); q != "" {
 		path += "?" + q
 	}
-	raw, err := c.requestRaw(http.MethodPut, path, bytes.NewReader(body))
+	raw, err := c.requestRaw(ctx, http.MethodPut, path, body)
 	if err != nil {
 		return nil, err
 	}
@@ -181,6 +324,68 @@ func (c *Client) UpdateSchemaRaw(body []byte, publish bool) (*schema.SchemaRevis
 	return &rev, nil
 }
 
+// QueryRecordsContext returns up to limit records of a table through the data query endpoint.
+// Numbers are decoded as json.Number so integers keep their precision. The query only reads, so it
+// is retried like a GET even though it is sent as a PUT.
+func (c *Client) QueryRecordsContext(ctx context.Context, table string, limit int) ([]map[string]any, error) {
+	path := fmt.Sprintf("/data/%s/query/%s?pageSize=%d", url.PathEscape(c.databaseID), url.PathEscape(table), limit)
+	body, err := json.Marshal(map[string]any{"type": "SelectQuery", "limit": limit})
+	if err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+	raw, err := c.send(ctx, http.MethodPut, path, body, true)
+	if err != nil {
+		return nil, err
+	}
+	var page struct {
+		Records []map[string]any `json:"records"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&page); err != nil {
+		return nil, fmt.Errorf("decode %s records: %w", table, err)
+	}
+	return page.Records, nil
+}
+
+// Context-free wrappers (context.Background()) kept for existing callers.
+
+func (c *Client) GetSchema(tables []string) (*schema.SchemaRevision, error) {
+	return c.GetSchemaContext(context.Background(), tables)
+}
+
+func (c *Client) GetSchemaRaw(tables []string) ([]byte, *schema.SchemaRevision, error) {
+	return c.GetSchemaRawContext(context.Background(), tables)
+}
+
+func (c *Client) ListSchemaRevisions() ([]schema.SchemaRevision, error) {
+	return c.ListSchemaRevisionsContext(context.Background())
+}
+
+func (c *Client) GetSchemaRevision(revisionID string) (*schema.SchemaRevision, error) {
+	return c.GetSchemaRevisionContext(context.Background(), revisionID)
+}
+
+func (c *Client) GetSchemaRevisionRaw(revisionID string) ([]byte, *schema.SchemaRevision, error) {
+	return c.GetSchemaRevisionRawContext(context.Background(), revisionID)
+}
+
+func (c *Client) ValidateSchema(req schema.SchemaUpsertRequest) (*schema.SchemaValidationResult, error) {
+	return c.ValidateSchemaContext(context.Background(), req)
+}
+
+func (c *Client) UpdateSchema(req schema.SchemaUpsertRequest, publish bool) (*schema.SchemaRevision, error) {
+	return c.UpdateSchemaContext(context.Background(), req, publish)
+}
+
+func (c *Client) ValidateSchemaRaw(body []byte) (*schema.SchemaValidationResult, error) {
+	return c.ValidateSchemaRawContext(context.Background(), body)
+}
+
+func (c *Client) UpdateSchemaRaw(body []byte, publish bool) (*schema.SchemaRevision, error) {
+	return c.UpdateSchemaRawContext(context.Background(), body, publish)
+}
+
 // ensureDatabaseIDRaw injects the configured database ID when the payload omits it, while preserving all other fields.
 func ensureDatabaseIDRaw(req schema.SchemaUpsertRequest, defaultDB string) ([]byte, error) {
 	raw, err := json.Marshal(req)
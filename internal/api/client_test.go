@@ -0,0 +1,261 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var fastRetry = RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+func TestGetSchema_RetriesTransientStatus(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"tables":[{"name":"User"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "db", "k", "s", WithRetryPolicy(fastRetry))
+	rev, err := c.GetSchema(nil)
+	if err != nil {
+		t.Fatalf("GetSchema: %v", err)
+	}
+	if len(rev.Tables) != 1 || calls != 3 {
+		t.Fatalf("tables=%d calls=%d; want 1 table after 3 calls", len(rev.Tables), calls)
+	}
+}
+
+func TestGetSchema_DoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "db", "k", "s", WithRetryPolicy(fastRetry))
+	if _, err := c.GetSchema(nil); err == nil {
+		t.Fatalf("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+}
+
+func TestGetSchema_StopsWhenContextCancelled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "db", "k", "s", WithRetryPolicy(RetryPolicy{MaxRetries: 5, BaseDelay: time.Millisecond, MaxDelay: time.Minute}))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := c.GetSchemaContext(ctx, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context deadline exceeded", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatalf("retry wait ignored context cancellation")
+	}
+}
+
+func TestUpdateSchema_DoesNotResendAfterTimeout(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release // applied server-side, but the response never arrives in time
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	c := NewClient(srv.URL, "db", "k", "s", WithTimeout(50*time.Millisecond), WithRetryPolicy(fastRetry))
+	if _, err := c.UpdateSchemaRaw([]byte(`{"tables":[]}`), true); err == nil {
+		t.Fatalf("expected timeout error")
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("publish sent %d times, want 1", n)
+	}
+}
+
+func TestUpdateSchema_RetriesOnlyWhenServerAsks(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		status     int
+		retryAfter string
+		wantCalls  int32
+	}{
+		{"server error", http.StatusInternalServerError, "", 1},
+		{"unavailable without Retry-After", http.StatusServiceUnavailable, "", 1},
+		{"unavailable with Retry-After", http.StatusServiceUnavailable, "1", 2},
+		{"rate limited with Retry-After", http.StatusTooManyRequests, "1", 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					if tc.retryAfter != "" {
+						w.Header().Set("Retry-After", tc.retryAfter)
+					}
+					w.WriteHeader(tc.status)
+					return
+				}
+				_, _ = w.Write([]byte(`{"tables":[]}`))
+			}))
+			defer srv.Close()
+
+			c := NewClient(srv.URL, "db", "k", "s", WithRetryPolicy(fastRetry))
+			_, _ = c.UpdateSchemaRaw([]byte(`{"tables":[]}`), true)
+			if calls != tc.wantCalls {
+				t.Fatalf("calls = %d, want %d", calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	if d := p.delay(0, 2*time.Second); d != time.Second {
+		t.Fatalf("Retry-After should be capped at MaxDelay, got %v", d)
+	}
+	if d := p.delay(0, 250*time.Millisecond); d != 250*time.Millisecond {
+		t.Fatalf("Retry-After should win over backoff, got %v", d)
+	}
+	for attempt := 0; attempt < 6; attempt++ {
+		if d := p.delay(attempt, 0); d <= 0 || d > time.Second {
+			t.Fatalf("delay(%d) = %v outside (0, 1s]", attempt, d)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if d := parseRetryAfter("3", now); d != 3*time.Second {
+		t.Fatalf("seconds form = %v", d)
+	}
+	if d := parseRetryAfter(now.Add(10*time.Second).Format(http.TimeFormat), now); d != 10*time.Second {
+		t.Fatalf("date form = %v", d)
+	}
+	if d := parseRetryAfter("soon", now); d != 0 {
+		t.Fatalf("invalid form = %v", d)
+	}
+}
+
+func TestAPIError_CategoriesAndPayload(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"error":{"message":"schema invalid","code":"SCHEMA_INVALID","errors":[{"message":"User.id missing"}]}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "db", "k", "s", WithRetryPolicy(fastRetry))
+	_, err := c.ValidateSchemaRaw([]byte(`{"tables":[]}`))
+	if !errors.Is(err, ErrValidation) || errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("errors.Is mismatch for %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+	if apiErr.StatusCode != 422 || apiErr.RequestID != "req-123" || apiErr.Category != CategoryValidation {
+		t.Fatalf("unexpected APIError fields: %#v", apiErr)
+	}
+	if apiErr.Payload.Message != "schema invalid" || apiErr.Payload.Code != "SCHEMA_INVALID" || len(apiErr.Payload.Errors) != 1 {
+		t.Fatalf("payload not parsed: %#v", apiErr.Payload)
+	}
+}
+
+func TestCategorize(t *testing.T) {
+	cases := map[int]ErrorCategory{
+		401: CategoryAuth,
+		403: CategoryAuth,
+		404: CategoryNotFound,
+		400: CategoryValidation,
+		429: CategoryRateLimited,
+		502: CategoryServer,
+		418: CategoryUnknown,
+	}
+	for status, want := range cases {
+		if got := categorize(status); got != want {
+			t.Fatalf("categorize(%d) = %q, want %q", status, got, want)
+		}
+	}
+}
+
+func TestGetSchemaRevision_FetchesOneRevisionByID(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		_, _ = w.Write([]byte(`{"meta":{"revisionId":"rev/1"},"entities":[{"name":"User"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "db", "k", "s", WithRetryPolicy(fastRetry))
+	rev, err := c.GetSchemaRevision("rev/1")
+	if err != nil {
+		t.Fatalf("GetSchemaRevision: %v", err)
+	}
+	if len(paths) != 1 || paths[0] != "/schemas/history/db/rev%2F1" {
+		t.Fatalf("requests = %v, want one GET /schemas/history/db/rev%%2F1", paths)
+	}
+	if len(rev.Tables) != 1 || rev.DatabaseID != "db" {
+		t.Fatalf("revision = %#v", rev)
+	}
+}
+
+func TestQueryRecords_SendsSelectQuery(t *testing.T) {
+	var gotPath, gotMethod string
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotMethod = r.URL.RequestURI(), r.Method
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		_, _ = w.Write([]byte(`{"records":[{"id":"1","count":9007199254740993}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "db 1", "k", "s", WithRetryPolicy(fastRetry))
+	records, err := c.QueryRecordsContext(context.Background(), "AuditLog", 25)
+	if err != nil {
+		t.Fatalf("QueryRecordsContext: %v", err)
+	}
+	if gotMethod != http.MethodPut || gotPath != "/data/db%201/query/AuditLog?pageSize=25" || gotBody["type"] != "SelectQuery" {
+		t.Fatalf("request = %s %s %v", gotMethod, gotPath, gotBody)
+	}
+	if len(records) != 1 || records[0]["count"] != json.Number("9007199254740993") {
+		t.Fatalf("records = %#v", records)
+	}
+}
+
+func TestQueryRecords_RetriesLikeAGet(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"records":[{"id":"1"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "db", "k", "s", WithRetryPolicy(fastRetry))
+	records, err := c.QueryRecordsContext(context.Background(), "User", 10)
+	if err != nil {
+		t.Fatalf("QueryRecordsContext: %v", err)
+	}
+	if len(records) != 1 || calls != 3 {
+		t.Fatalf("records=%d calls=%d; want 1 record after 3 calls", len(records), calls)
+	}
+}
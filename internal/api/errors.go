@@ -0,0 +1,164 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/OnyxDevTools/onyx-cli/internal/schema"
+)
+
+// ErrorCategory groups API failures by what the caller can do about them.
+type ErrorCategory string
+
+const (
+	CategoryAuth        ErrorCategory = "auth"         // 401/403: bad or missing credentials
+	CategoryNotFound    ErrorCategory = "not_found"    // 404: unknown database or revision
+	CategoryValidation  ErrorCategory = "validation"   // 400/409/422: request rejected as invalid
+	CategoryRateLimited ErrorCategory = "rate_limited" // 429
+	CategoryServer      ErrorCategory = "server"       // 5xx
+	CategoryUnknown     ErrorCategory = "unknown"
+)
+
+// Sentinel errors for errors.Is checks against an *APIError's category.
+var (
+	ErrUnauthorized = errors.New("onyx api: unauthorized")
+	ErrNotFound     = errors.New("onyx api: not found")
+	ErrValidation   = errors.New("onyx api: validation failed")
+	ErrRateLimited  = errors.New("onyx api: rate limited")
+	ErrServer       = errors.New("onyx api: server error")
+)
+
+// ErrorPayload is the decoded error body returned by the Schema API (all fields optional).
+type ErrorPayload struct {
+	Message string                   `json:"message,omitempty"`
+	Code    string                   `json:"code,omitempty"`
+	Errors  []schema.ValidationError `json:"errors,omitempty"`
+}
+
+// APIError describes a non-2xx response from the Schema API.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	RequestID  string
+	Category   ErrorCategory
+	Payload    ErrorPayload
+	Body       string // raw response body (trimmed)
+}
+
+func (e *APIError) Error() string {
+	msg := e.Payload.Message
+	if msg == "" {
+		msg = e.Body
+	}
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	for _, v := range e.Payload.Errors {
+		if m := strings.TrimSpace(v.Message); m != "" {
+			msg += "; " + m
+		}
+	}
+	out := fmt.Sprintf("%s %s: %s (HTTP %d", e.Method, e.Path, msg, e.StatusCode)
+	if e.RequestID != "" {
+		out += ", request id " + e.RequestID
+	}
+	return out + ")"
+}
+
+// Is lets errors.Is match the category sentinels (ErrUnauthorized, ErrNotFound, ...).
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.Category == CategoryAuth
+	case ErrNotFound:
+		return e.Category == CategoryNotFound
+	case ErrValidation:
+		return e.Category == CategoryValidation
+	case ErrRateLimited:
+		return e.Category == CategoryRateLimited
+	case ErrServer:
+		return e.Category == CategoryServer
+	}
+	return false
+}
+
+// Retryable reports whether the status is worth retrying (429 and 5xx).
+func (e *APIError) Retryable() bool {
+	return e.Category == CategoryRateLimited || e.Category == CategoryServer
+}
+
+func categorize(status int) ErrorCategory {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return CategoryAuth
+	case status == http.StatusNotFound:
+		return CategoryNotFound
+	case status == http.StatusBadRequest || status == http.StatusConflict || status == http.StatusUnprocessableEntity:
+		return CategoryValidation
+	case status == http.StatusTooManyRequests:
+		return CategoryRateLimited
+	case status >= 500:
+		return CategoryServer
+	default:
+		return CategoryUnknown
+	}
+}
+
+// newAPIError builds an APIError from a non-2xx response.
+func newAPIError(method, path string, res *http.Response, body []byte) *APIError {
+	e := &APIError{
+		Method:     method,
+		Path:       path,
+		StatusCode: res.StatusCode,
+		RequestID:  firstHeader(res.Header, "X-Request-Id", "X-Onyx-Request-Id", "X-Amzn-Requestid"),
+		Category:   categorize(res.StatusCode),
+		Body:       strings.TrimSpace(string(body)),
+	}
+	e.Payload = parseErrorPayload(body)
+	return e
+}
+
+// parseErrorPayload understands {"message": ...}, {"error": "..."}, and {"error": {"message": ...}} shapes.
+func parseErrorPayload(body []byte) ErrorPayload {
+	var raw struct {
+		ErrorPayload
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return ErrorPayload{}
+	}
+	p := raw.ErrorPayload
+	if len(raw.Error) > 0 {
+		var s string
+		var nested ErrorPayload
+		if json.Unmarshal(raw.Error, &s) == nil {
+			if p.Message == "" {
+				p.Message = s
+			}
+		} else if json.Unmarshal(raw.Error, &nested) == nil {
+			if p.Message == "" {
+				p.Message = nested.Message
+			}
+			if p.Code == "" {
+				p.Code = nested.Code
+			}
+			if len(p.Errors) == 0 {
+				p.Errors = nested.Errors
+			}
+		}
+	}
+	return p
+}
+
+func firstHeader(h http.Header, names ...string) string {
+	for _, n := range names {
+		if v := strings.TrimSpace(h.Get(n)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how transient failures are retried.
+type RetryPolicy struct {
+	MaxRetries int           // retries after the first attempt; 0 disables retrying
+	BaseDelay  time.Duration // backoff for the first retry, doubled per attempt
+	MaxDelay   time.Duration // cap for computed backoff and Retry-After waits
+}
+
+// DefaultRetryPolicy retries up to 3 times with 500ms, 1s, 2s (jittered) backoff.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   30 * time.Second,
+}
+
+// delay returns the wait before retry number attempt (0-based). A server-provided Retry-After wins
+// over the computed backoff; otherwise "full jitter" picks a random wait up to the exponential cap.
+func (p RetryPolicy) delay(attempt int, retryAfter time.Duration) time.Duration {
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryPolicy.MaxDelay
+	}
+	if retryAfter > 0 {
+		if retryAfter > maxDelay {
+			return maxDelay
+		}
+		return retryAfter
+	}
+	backoff := p.BaseDelay
+	if backoff <= 0 {
+		return 0
+	}
+	for i := 0; i < attempt && backoff < maxDelay; i++ {
+		backoff *= 2
+	}
+	if backoff > maxDelay {
+		backoff = maxDelay
+	}
+	return time.Duration(rand.Int63n(int64(backoff)) + 1)
+}
+
+// isRetryable reports whether a failed attempt may be sent again. Idempotent requests (GETs and
+// read-only queries) retry on 429, 5xx, connection resets, and timeouts. Other requests (validate,
+// publish) may already have been applied when the response is lost, so they retry only when the
+// request never reached the server (dial failures) or when the server asked for a retry with 429/503
+// and Retry-After. Cancellation of the caller's context is never retried.
+func isRetryable(ctx context.Context, idempotent bool, err error, retryAfter time.Duration) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *APIError
+	if !idempotent {
+		if errors.As(err, &apiErr) {
+			return retryAfter > 0 && (apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode == http.StatusServiceUnavailable)
+		}
+		var opErr *net.OpError
+		return errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// parseRetryAfter understands both delta-seconds and HTTP-date forms of Retry-After.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs <= 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
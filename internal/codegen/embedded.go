@@ -0,0 +1,357 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ShapeKey is the optional attribute key describing the value of an embedded object, e.g.
+//
+//	{"name": "changes", "type": "EmbeddedObject",
+//	 "shape": [{"field": "String", "before": "JSON", "after": "JSON", "at": "Date", "tags": "String[]"}]}
+//
+// A shape is an attribute type ("String", or "String[]" for a list), an object of named shapes,
+// or a one-element array holding the shape of list items. Generators emit named nested types
+// (<Table><Attribute>, <Table><Attribute><Field>, ...) for object shapes. Shapes are local to
+// codegen; they are not part of the published schema.
+const ShapeKey = "shape"
+
+// ShapeField describes a value inside an embedded object. Fields is set for objects; otherwise
+// Type is the attribute type of the value. List marks an array of such values.
+type ShapeField struct {
+	Name   string
+	Type   string
+	List   bool
+	Fields []ShapeField
+}
+
+// IsObject reports whether the value is an object with known fields.
+func (s ShapeField) IsObject() bool { return len(s.Fields) > 0 }
+
+// isEmbeddedType reports whether values of an attribute type are free-form objects.
+func isEmbeddedType(schemaType string) bool {
+	switch strings.ToLower(strings.TrimSpace(schemaType)) {
+	case "json", "object", "record", "map", "embeddedobject":
+		return true
+	}
+	return false
+}
+
+// parseShape decodes a shape hint; object fields are sorted by name.
+func parseShape(name string, raw json.RawMessage) (ShapeField, error) {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return ShapeField{}, err
+	}
+	return shapeFromHint(name, v)
+}
+
+func shapeFromHint(name string, v any) (ShapeField, error) {
+	switch t := v.(type) {
+	case string:
+		typ := strings.TrimSpace(t)
+		list := strings.HasSuffix(typ, "[]")
+		typ = strings.TrimSpace(strings.TrimSuffix(typ, "[]"))
+		if typ == "" {
+			return ShapeField{}, fmt.Errorf("%s: empty type", name)
+		}
+		return ShapeField{Name: name, Type: typ, List: list}, nil
+	case map[string]any:
+		s := ShapeField{Name: name}
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			f, err := shapeFromHint(k, t[k])
+			if err != nil {
+				return ShapeField{}, fmt.Errorf("%s.%w", name, err)
+			}
+			s.Fields = append(s.Fields, f)
+		}
+		if len(s.Fields) == 0 {
+			s.Type = "JSON"
+		}
+		return s, nil
+	case []any:
+		if len(t) != 1 {
+			return ShapeField{}, fmt.Errorf("%s: a list shape holds exactly one item shape", name)
+		}
+		item, err := shapeFromHint(name, t[0])
+		if err != nil {
+			return ShapeField{}, err
+		}
+		if item.List {
+			return ShapeField{}, fmt.Errorf("%s: nested lists are not supported", name)
+		}
+		item.List = true
+		return item, nil
+	}
+	return ShapeField{}, fmt.Errorf("%s: expected a type name, an object or a one-item list", name)
+}
+
+// hint returns the schema-file form of a shape (the inverse of parseShape).
+func (s ShapeField) hint() any {
+	if s.IsObject() {
+		m := make(map[string]any, len(s.Fields))
+		for _, f := range s.Fields {
+			m[f.Name] = f.hint()
+		}
+		if s.List {
+			return []any{m}
+		}
+		return m
+	}
+	if s.List {
+		return s.Type + "[]"
+	}
+	return s.Type
+}
+
+// embeddedShapes returns the shapes declared on embedded attributes, by table and attribute name.
+func embeddedShapes(schemaJSON []byte) (map[string]map[string]ShapeField, error) {
+	var doc struct {
+		Tables   []shapeTable `json:"tables"`
+		Entities []shapeTable `json:"entities"`
+	}
+	if err := json.Unmarshal(schemaJSON, &doc); err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+	tables := doc.Tables
+	if len(tables) == 0 {
+		tables = doc.Entities
+	}
+	out := map[string]map[string]ShapeField{}
+	for _, t := range tables {
+		attrs := t.Attributes
+		if len(attrs) == 0 {
+			attrs = t.Fields
+		}
+		for _, a := range attrs {
+			if len(a.Shape) == 0 || string(a.Shape) == "null" || !isEmbeddedType(a.Type) {
+				continue
+			}
+			s, err := parseShape(a.Name, a.Shape)
+			if err != nil {
+				return nil, fmt.Errorf("table %s: invalid %s: %w", t.Name, ShapeKey, err)
+			}
+			if out[t.Name] == nil {
+				out[t.Name] = map[string]ShapeField{}
+			}
+			out[t.Name][a.Name] = s
+		}
+	}
+	return out, nil
+}
+
+type shapeTable struct {
+	Name       string           `json:"name"`
+	Attributes []shapeAttribute `json:"attributes"`
+	Fields     []shapeAttribute `json:"fields"`
+}
+
+type shapeAttribute struct {
+	Name  string          `json:"name"`
+	Type  string          `json:"type"`
+	Shape json.RawMessage `json:"shape"`
+}
+
+// shapeTypeName names the nested type of an embedded attribute or field.
+func shapeTypeName(parent, field string) string { return exportName(parent) + exportName(field) }
+
+// eachShapeType calls fn for every object type inside s, innermost first, so languages that
+// need definitions before use can emit them in order. path names the value, e.g. "AuditLog.changes".
+func eachShapeType(typeName, path string, s ShapeField, fn func(typeName, path string, fields []ShapeField)) {
+	if !s.IsObject() {
+		return
+	}
+	for _, f := range s.Fields {
+		eachShapeType(shapeTypeName(typeName, f.Name), path+"."+f.Name, f, fn)
+	}
+	fn(typeName, path, s.Fields)
+}
+
+// shapeType renders the type of a shape value in a target language: typeName for objects,
+// scalar(Type) otherwise, wrapped with list for arrays.
+func shapeType(typeName string, s ShapeField, scalar func(string) string, list func(string) string) string {
+	base := typeName
+	if !s.IsObject() {
+		base = scalar(s.Type)
+	}
+	if s.List {
+		return list(base)
+	}
+	return base
+}
+
+// shapeUses reports whether s or any value nested in it satisfies pred.
+func shapeUses(s ShapeField, pred func(ShapeField) bool) bool {
+	if pred(s) {
+		return true
+	}
+	for _, f := range s.Fields {
+		if shapeUses(f, pred) {
+			return true
+		}
+	}
+	return false
+}
+
+// InferEmbeddedShapes adds shapes, inferred from sample records, to embedded attributes that do
+// not declare one. sample returns records of a table; it is called once per table with embedded
+// attributes. Attributes without object or list values in the sample are left untyped.
+func InferEmbeddedShapes(schemaJSON []byte, sample func(table string) ([]map[string]any, error)) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(schemaJSON))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+	tables, _ := doc["tables"].([]any)
+	if len(tables) == 0 {
+		tables, _ = doc["entities"].([]any)
+	}
+	for _, rawTable := range tables {
+		table, _ := rawTable.(map[string]any)
+		name, _ := table["name"].(string)
+		attrs, _ := table["attributes"].([]any)
+		var pending []map[string]any
+		for _, rawAttr := range attrs {
+			attr, _ := rawAttr.(map[string]any)
+			typ, _ := attr["type"].(string)
+			if attr == nil || !isEmbeddedType(typ) || attr[ShapeKey] != nil {
+				continue
+			}
+			pending = append(pending, attr)
+		}
+		if name == "" || len(pending) == 0 {
+			continue
+		}
+		records, err := sample(name)
+		if err != nil {
+			return nil, fmt.Errorf("sample %s: %w", name, err)
+		}
+		for _, attr := range pending {
+			attrName, _ := attr["name"].(string)
+			var s *ShapeField
+			for _, rec := range records {
+				s = mergeShape(s, inferShape(attrName, rec[attrName]))
+			}
+			if s != nil && (s.IsObject() || s.List) {
+				attr[ShapeKey] = s.hint()
+			}
+		}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// inferShape describes one sampled value; nil for null values.
+func inferShape(name string, v any) *ShapeField {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		s := &ShapeField{Name: name}
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if f := inferShape(k, t[k]); f != nil {
+				s.Fields = append(s.Fields, *f)
+			}
+		}
+		if len(s.Fields) == 0 {
+			s.Type = "JSON"
+		}
+		return s
+	case []any:
+		var item *ShapeField
+		for _, e := range t {
+			item = mergeShape(item, inferShape(name, e))
+		}
+		if item == nil || item.List {
+			return &ShapeField{Name: name, Type: "JSON", List: true}
+		}
+		item.List = true
+		return item
+	case string:
+		if _, err := time.Parse(time.RFC3339, t); err == nil {
+			return &ShapeField{Name: name, Type: "Date"}
+		}
+		return &ShapeField{Name: name, Type: "String"}
+	case bool:
+		return &ShapeField{Name: name, Type: "Boolean"}
+	case json.Number:
+		if _, err := t.Int64(); err == nil {
+			return &ShapeField{Name: name, Type: "Long"}
+		}
+		return &ShapeField{Name: name, Type: "Double"}
+	case float64:
+		if t == float64(int64(t)) {
+			return &ShapeField{Name: name, Type: "Long"}
+		}
+		return &ShapeField{Name: name, Type: "Double"}
+	}
+	return &ShapeField{Name: name, Type: "JSON"}
+}
+
+// mergeShape combines two observations of the same value: object fields are unioned, Long and
+// Double widen to Double, Date and String to String, and other conflicts fall back to JSON.
+func mergeShape(a, b *ShapeField) *ShapeField {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	out := ShapeField{Name: a.Name, List: a.List}
+	if a.List != b.List {
+		return &ShapeField{Name: a.Name, Type: "JSON"}
+	}
+	switch {
+	case a.IsObject() && b.IsObject():
+		byName := map[string]*ShapeField{}
+		var names []string
+		for _, fields := range [][]ShapeField{a.Fields, b.Fields} {
+			for i := range fields {
+				f := fields[i]
+				if _, ok := byName[f.Name]; !ok {
+					names = append(names, f.Name)
+				}
+				byName[f.Name] = mergeShape(byName[f.Name], &f)
+			}
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			out.Fields = append(out.Fields, *byName[n])
+		}
+	case a.IsObject() || b.IsObject():
+		out.Type = "JSON"
+	case a.Type == b.Type:
+		out.Type = a.Type
+	case isNumberShape(a.Type) && isNumberShape(b.Type):
+		out.Type = "Double"
+	case isTextShape(a.Type) && isTextShape(b.Type):
+		out.Type = "String"
+	default:
+		out.Type = "JSON"
+	}
+	return &out
+}
+
+func isNumberShape(t string) bool { return t == "Long" || t == "Double" }
+
+func isTextShape(t string) bool { return t == "String" || t == "Date" }
@@ -0,0 +1,134 @@
+package codegen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const embeddedSchemaJSON = `{
+  "tables": [
+    {"name": "AuditLog", "identifier": {"name": "id"},
+     "attributes": [
+       {"name": "id", "type": "String"},
+       {"name": "changes", "type": "EmbeddedObject", "shape": [{"field": "String", "at": "Date", "meta": {"by": "String"}, "tags": "String[]"}]},
+       {"name": "context", "type": "JSON", "isNullable": true, "shape": {"ip": "String"}},
+       {"name": "title", "type": "String", "shape": {"ignored": "String"}}
+     ]}
+  ]
+}`
+
+func TestEmbeddedShapes_ParsesHints(t *testing.T) {
+	shapes, err := embeddedShapes([]byte(embeddedSchemaJSON))
+	if err != nil {
+		t.Fatalf("embeddedShapes: %v", err)
+	}
+	changes := shapes["AuditLog"]["changes"]
+	if !changes.List || !changes.IsObject() || len(changes.Fields) != 4 || changes.Fields[0].Name != "at" {
+		t.Fatalf("changes shape = %+v", changes)
+	}
+	if tags := changes.Fields[3]; tags.Type != "String" || !tags.List {
+		t.Fatalf("tags shape = %+v", tags)
+	}
+	if _, ok := shapes["AuditLog"]["title"]; ok {
+		t.Fatal("shapes on scalar attributes should be ignored")
+	}
+	if _, err := embeddedShapes([]byte(`{"tables":[{"name":"T","attributes":[{"name":"a","type":"JSON","shape":[["String"]]}]}]}`)); err == nil {
+		t.Fatal("expected an error for nested lists")
+	}
+}
+
+func TestRenderers_EmitNestedTypes(t *testing.T) {
+	tables, _, err := parseTables([]byte(embeddedSchemaJSON))
+	if err != nil {
+		t.Fatalf("parseTables: %v", err)
+	}
+	goOut := renderTableText(t, tables[0])
+	ts, err := RenderTypescriptTypes([]byte(embeddedSchemaJSON), "OnyxSchema")
+	if err != nil {
+		t.Fatalf("RenderTypescriptTypes: %v", err)
+	}
+	kt, err := RenderKotlinTypes([]byte(embeddedSchemaJSON), "onyx")
+	if err != nil {
+		t.Fatalf("RenderKotlinTypes: %v", err)
+	}
+	for _, tc := range []struct {
+		lang, out string
+		want      []string
+	}{
+		{"go", goOut, []string{
+			"Changes []AuditLogChanges `json:\"changes,omitempty\"`",
+			"Context *AuditLogContext `json:\"context,omitempty\"`",
+			"type AuditLogChangesMeta struct {",
+			"Meta *AuditLogChangesMeta `json:\"meta,omitempty\"`",
+			"Tags []string `json:\"tags,omitempty\"`",
+			"SetChanges(v []AuditLogChanges)",
+		}},
+		{"typescript", ts, []string{"export interface AuditLogChanges {", "changes?: AuditLogChanges[];", "context?: AuditLogContext | null;", "at?: Date;"}},
+		{"kotlin", kt, []string{"data class AuditLogChangesMeta(", "val changes: List<AuditLogChanges>,", "val tags: List<String>? = null,"}},
+	} {
+		for _, want := range tc.want {
+			if !strings.Contains(tc.out, want) {
+				t.Fatalf("%s output missing %q:\n%s", tc.lang, want, tc.out)
+			}
+		}
+	}
+	dir := t.TempDir()
+	if err := RenderJavaTypes([]byte(embeddedSchemaJSON), "onyx", dir, true); err != nil {
+		t.Fatalf("RenderJavaTypes: %v", err)
+	}
+	java, err := os.ReadFile(filepath.Join(dir, "onyx", "AuditLog.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"import java.util.List;", "public List<AuditLogChanges> changes;", "public static class AuditLogChangesMeta {"} {
+		if !strings.Contains(string(java), want) {
+			t.Fatalf("java output missing %q:\n%s", want, java)
+		}
+	}
+}
+
+func TestInferEmbeddedShapes_MergesSamples(t *testing.T) {
+	schema := `{"tables":[{"name":"AuditLog","attributes":[
+  {"name":"id","type":"String"},
+  {"name":"changes","type":"EmbeddedObject"},
+  {"name":"context","type":"EmbeddedObject","shape":{"ip":"String"}},
+  {"name":"note","type":"JSON"}]}]}`
+	var sampled []string
+	out, err := InferEmbeddedShapes([]byte(schema), func(table string) ([]map[string]any, error) {
+		sampled = append(sampled, table)
+		return []map[string]any{
+			{"changes": []any{map[string]any{"field": "email", "n": json.Number("1"), "at": "2024-01-01T00:00:00Z"}}, "note": "text"},
+			{"changes": []any{map[string]any{"field": "age", "n": json.Number("2.5"), "at": "later", "by": map[string]any{"user": "u1"}}}},
+			{"changes": nil},
+		}, nil
+	})
+	if err != nil {
+		t.Fatalf("InferEmbeddedShapes: %v", err)
+	}
+	if !reflect.DeepEqual(sampled, []string{"AuditLog"}) {
+		t.Fatalf("sampled tables = %v", sampled)
+	}
+	var doc struct {
+		Tables []struct {
+			Attributes []map[string]any `json:"attributes"`
+		} `json:"tables"`
+	}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatal(err)
+	}
+	attrs := doc.Tables[0].Attributes
+	want := []any{map[string]any{"at": "String", "by": map[string]any{"user": "String"}, "field": "String", "n": "Double"}}
+	if !reflect.DeepEqual(attrs[1]["shape"], want) {
+		t.Fatalf("inferred changes shape = %#v", attrs[1]["shape"])
+	}
+	if !reflect.DeepEqual(attrs[2]["shape"], map[string]any{"ip": "String"}) {
+		t.Fatalf("declared shape was replaced: %#v", attrs[2]["shape"])
+	}
+	if _, ok := attrs[3]["shape"]; ok {
+		t.Fatalf("scalar samples should not produce a shape: %#v", attrs[3])
+	}
+}
@@ -1,6 +1,11 @@
 package codegen
 
 import (
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -74,22 +79,248 @@ func TestParseTables_RespectsNullableFlag(t *testing.T) {
 }
 
 func TestMapGoType_UsesPointersForNullable(t *testing.T) {
+	// Pointers are opt-in (--go-pointer-fields) and only apply to nullable fields.
 	cases := []struct {
 		schemaType string
 		nullable   bool
+		pointer    bool
 		want       string
 	}{
-		{"String", true, "*string"},
-		{"Boolean", true, "*bool"},
-		{"Int", true, "*int64"},
-		{"Float", true, "*float64"},
-		{"Timestamp", true, "*time.Time"},
-		{"String", false, "string"},
-		{"Timestamp", false, "time.Time"},
+		{"String", true, true, "*string"},
+		{"Boolean", true, true, "*bool"},
+		{"Int", true, true, "*int64"},
+		{"Float", true, true, "*float64"},
+		{"Timestamp", true, true, "*time.Time"},
+		{"String", false, true, "string"},
+		{"Timestamp", false, true, "time.Time"},
+		{"String", true, false, "string"},
+		{"Timestamp", true, false, "time.Time"},
 	}
 	for _, tc := range cases {
-		if got := mapGoType(tc.schemaType, tc.nullable, false); got != tc.want {
-			t.Fatalf("mapGoType(%q, nullable=%t) = %q, want %q", tc.schemaType, tc.nullable, got, tc.want)
+		if got := mapGoType(tc.schemaType, tc.nullable, tc.pointer); got != tc.want {
+			t.Fatalf("mapGoType(%q, nullable=%t, pointer=%t) = %q, want %q", tc.schemaType, tc.nullable, tc.pointer, got, tc.want)
 		}
 	}
 }
+
+func TestParseTables_CarriesIdentifier(t *testing.T) {
+	const schema = `{
+  "tables": [
+    {"name": "Account", "identifier": {"name": "userId", "generator": "Sequence"}, "attributes": [{"name": "userId", "type": "Long"}]},
+    {"name": "Counter", "identifier": {"name": "seq", "generator": "Sequence"}, "attributes": []},
+    {"name": "Legacy", "fields": [{"name": "key", "type": "String", "primaryKey": true}]},
+    {"name": "Plain", "attributes": [{"name": "id", "type": "String"}]}
+  ]
+}`
+	tables, _, err := parseTables([]byte(schema))
+	if err != nil {
+		t.Fatalf("parseTables returned error: %v", err)
+	}
+	want := map[string]Field{
+		"Account": {Name: "userId", Type: "Long"},
+		"Counter": {Name: "seq", Type: "Long"},
+		"Legacy":  {Name: "key", Type: "String"},
+		"Plain":   {Name: "id", Type: "String"},
+	}
+	for _, tbl := range tables {
+		if tbl.Identifier != want[tbl.Name] {
+			t.Fatalf("%s identifier = %+v, want %+v", tbl.Name, tbl.Identifier, want[tbl.Name])
+		}
+	}
+}
+
+func TestRenderTable(t *testing.T) {
+	cases := []struct {
+		name      string
+		schema    string
+		table     string
+		resolvers []string // resolver names parseTables reports for the table, if checked
+		want      []string
+		unwanted  []string
+	}{
+		{
+			name: "identifier name and type",
+			schema: `{"tables": [{"name": "Account", "identifier": {"name": "userId", "type": "Long"},
+  "attributes": [{"name": "userId", "type": "Long"}]}]}`,
+			table: "Account",
+			want: []string{
+				"FindByID(ctx context.Context, userId int64) (Account, error)",
+				"DeleteByIDs(ctx context.Context, userIds []int64) (int, error)",
+				`onyx.Eq("userId", userId)`,
+				`onyx.In("userId", toAnyValues(userIds))`,
+				"c.core.Delete(ctx, Tables.Account, fmt.Sprint(userId))",
+			},
+			unwanted: []string{`"id"`},
+		},
+		{
+			name: "finders from indexes",
+			schema: `{"tables": [{"name": "Member", "identifier": {"name": "id", "type": "String"},
+  "attributes": [{"name": "id", "type": "String"}, {"name": "email", "type": "String"}, {"name": "isActive", "type": "Boolean"}, {"name": "age", "type": "Int"}],
+  "indexes": [{"name": "email", "unique": true}, {"name": "age"}, {"name": "id"}, {"name": "missing"}]}]}`,
+			table: "Member",
+			want: []string{
+				"FindByEmail(ctx context.Context, email string) (Member, error)",
+				"CountByEmail(ctx context.Context, email string) (int, error)",
+				"ListByAge(ctx context.Context, age int64) ([]Member, error)",
+				"CountByAge(ctx context.Context, age int64) (int, error)",
+			},
+			unwanted: []string{"FindActiveUsers", "CountActive", "ListByEmail", "FindByAge", "ListByID", "ByMissing"},
+		},
+		{
+			name: "typed fields and conditions",
+			schema: `{"tables": [{"name": "Member", "identifier": {"name": "id", "type": "String"},
+  "attributes": [{"name": "id", "type": "String"}, {"name": "isActive", "type": "Boolean"}, {"name": "age", "type": "Int", "isNullable": true}]}]}`,
+			table: "Member",
+			want: []string{
+				"type MemberField string",
+				"IsActive: \"isActive\",",
+				"var MemberWhere MemberConditions",
+				"func (MemberConditions) AgeBetween(from, to int64) onyx.Condition",
+				"func (MemberConditions) IdStartsWith(v string) onyx.Condition",
+				"func (MemberConditions) IsActiveEq(v bool) onyx.Condition",
+				"func (MemberConditions) AgeIsNull() onyx.Condition",
+				"OrderBy(field MemberField, asc bool) MembersClient",
+				"Select(fields ...MemberField) MembersMapClient",
+			},
+			// range and string builders follow the attribute type
+			unwanted: []string{"IsActiveGt(", "AgeLike("},
+		},
+		{
+			name: "typed resolvers",
+			schema: `{
+  "tables": [
+    {"name": "User", "attributes": [{"name": "id", "type": "String"}],
+     "resolvers": [
+       {"name": "roles", "resolver": "db.from(\"Role\")\n .where(inOp(\"id\", db.from(\"UserRole\").where(eq(\"userId\", this.id)).list().values('roleId')))\n .list()"},
+       {"name": "profile", "resolver": "db.from('UserProfile').where(eq('userId', this.id)).firstOrNull();"},
+       {"name": "stats", "resolver": "db.from(\"Role\").count()"},
+       {"name": "manager", "resolver": "lookupManager(this)", "returns": "user"},
+       {"name": "ghosts", "resolver": "db.from(\"Ghost\").list()"}
+     ]},
+    {"name": "Role", "attributes": [{"name": "id", "type": "String"}]},
+    {"name": "UserProfile", "attributes": [{"name": "id", "type": "String"}]}
+  ]
+}`,
+			table:     "User",
+			resolvers: []string{"roles", "profile", "stats", "manager", "ghosts"},
+			want: []string{
+				"Roles []Role `json:\"roles,omitempty\"`",
+				"Profile *UserProfile `json:\"profile,omitempty\"`",
+				"Stats any `json:\"stats,omitempty\"`",
+				"Manager *User `json:\"manager,omitempty\"`",
+				"Ghosts any `json:\"ghosts,omitempty\"`",
+				"\tResolveRoles() UsersClient\n",
+				"func (c UsersClient) ResolveProfile() UsersClient { return c.Resolve(\"profile\") }",
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tables, resolvers, err := parseTables([]byte(c.schema))
+			if err != nil {
+				t.Fatalf("parseTables returned error: %v", err)
+			}
+			if c.resolvers != nil && strings.Join(resolvers[c.table], ",") != strings.Join(c.resolvers, ",") {
+				t.Fatalf("resolver names = %v, want %v", resolvers[c.table], c.resolvers)
+			}
+			var out string
+			for _, tbl := range tables {
+				if tbl.Name == c.table {
+					out = renderTableText(t, tbl)
+				}
+			}
+			if out == "" {
+				t.Fatalf("table %s not parsed", c.table)
+			}
+			for _, want := range c.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("generated code missing %q", want)
+				}
+			}
+			for _, unwanted := range c.unwanted {
+				if strings.Contains(out, unwanted) {
+					t.Errorf("generated code should not contain %q", unwanted)
+				}
+			}
+		})
+	}
+}
+
+var alignedSpaces = regexp.MustCompile(` {2,}`)
+
+// renderTableText renders a table with the embedded templates and collapses gofmt's column
+// alignment so tests can match single-spaced snippets.
+func renderTableText(t *testing.T, table Table) string {
+	t.Helper()
+	tmpl, err := goTemplates("")
+	if err != nil {
+		t.Fatalf("goTemplates returned error: %v", err)
+	}
+	out, err := renderTable(tmpl, table, "onyx", false)
+	if err != nil {
+		t.Fatalf("renderTable returned error: %v", err)
+	}
+	return alignedSpaces.ReplaceAllString(string(out), " ")
+}
+
+func TestRenderGo_OutputIsGofmted(t *testing.T) {
+	const schema = `{
+  "tables": [
+    {"name": "User", "attributes": [{"name": "id", "type": "String"}, {"name": "age", "type": "Int"}],
+     "indexes": [{"name": "age"}],
+     "resolvers": [{"name": "roles", "resolver": "db.from(\"Role\").list()"}]},
+    {"name": "Role", "attributes": [{"name": "id", "type": "String"}]}
+  ]
+}`
+	dir := t.TempDir()
+	if err := RenderGoCommon([]byte(schema), dir, "onyx", true, false, ""); err != nil {
+		t.Fatalf("RenderGoCommon returned error: %v", err)
+	}
+	if err := RenderGoTables([]byte(schema), dir, "onyx", true, false, ""); err != nil {
+		t.Fatalf("RenderGoTables returned error: %v", err)
+	}
+	for _, name := range []string{"common.go", "user.go", "role.go"} {
+		src, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		formatted, err := format.Source(src)
+		if err != nil {
+			t.Fatalf("%s does not parse: %v", name, err)
+		}
+		if string(formatted) != string(src) {
+			t.Fatalf("%s is not gofmt-clean", name)
+		}
+	}
+}
+
+func TestRenderGo_TemplateOverrides(t *testing.T) {
+	const schema = `{"tables": [{"name": "User", "attributes": [{"name": "id", "type": "String"}]}]}`
+	templates := t.TempDir()
+	override := "// {{.Type}} is a house-style model.\ntype {{.Type}} struct {\n\tID string `json:\"id\"`\n}\n"
+	if err := os.WriteFile(filepath.Join(templates, "model.tmpl"), []byte(override), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	if err := RenderGoTables([]byte(schema), out, "onyx", true, false, templates); err != nil {
+		t.Fatalf("RenderGoTables returned error: %v", err)
+	}
+	src, err := os.ReadFile(filepath.Join(out, "user.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), "// User is a house-style model.") {
+		t.Fatalf("override not applied:\n%s", src)
+	}
+	if !strings.Contains(string(src), "type UsersClient struct") {
+		t.Fatal("templates that are not overridden should still render")
+	}
+
+	if err := os.WriteFile(filepath.Join(templates, "modle.tmpl"), []byte(""), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err = RenderGoTables([]byte(schema), out, "onyx", true, false, templates)
+	if err == nil || !strings.Contains(err.Error(), "unknown template modle.tmpl") {
+		t.Fatalf("expected unknown template error, got %v", err)
+	}
+}
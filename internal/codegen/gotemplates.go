@@ -0,0 +1,275 @@
+package codegen
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// goTemplateFS holds the templates the Go client is rendered from: common.go.tmpl and
+// table.go.tmpl produce files, the others are sections included by table.go.tmpl.
+//
+//go:embed templates/go/*.tmpl
+var goTemplateFS embed.FS
+
+var goTemplateFuncs = template.FuncMap{"quote": strconv.Quote}
+
+// goTemplates parses the embedded Go templates, then the *.tmpl files in dir, if set, each of
+// which replaces the embedded template with the same file name.
+func goTemplates(dir string) (*template.Template, error) {
+	tmpl, err := template.New("go").Funcs(goTemplateFuncs).ParseFS(goTemplateFS, "templates/go/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		return tmpl, nil
+	}
+	if info, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("templates: %w", err)
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("templates: %s is not a directory", dir)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("templates: no .tmpl files in %s", dir)
+	}
+	for _, file := range files {
+		name := filepath.Base(file)
+		if tmpl.Lookup(name) == nil {
+			return nil, fmt.Errorf("templates: unknown template %s (expected one of %s)", name, strings.Join(GoTemplateNames(), ", "))
+		}
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tmpl.New(name).Parse(string(b)); err != nil {
+			return nil, fmt.Errorf("templates: %w", err)
+		}
+	}
+	return tmpl, nil
+}
+
+// GoTemplateNames lists the templates that can be overridden with onyx gen --templates.
+func GoTemplateNames() []string {
+	entries, _ := goTemplateFS.ReadDir("templates/go")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// executeGo renders a file template and gofmts the result.
+func executeGo(tmpl *template.Template, name string, data any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%s: generated code does not parse: %w", name, err)
+	}
+	return src, nil
+}
+
+// goCommonData is the data of common.go.tmpl.
+type goCommonData struct {
+	GeneratedAt string
+	Package     string
+	Tables      []goTableName
+	Resolvers   []goTableResolvers
+}
+
+type goTableName struct {
+	Type string // exported Go name, e.g. "User"
+	Name string // table name
+}
+
+type goTableResolvers struct {
+	Table string
+	Names []string
+}
+
+// goTableData is the data of table.go.tmpl and its sections. Type names are derived from the
+// exported table name (User → UsersClient, UserPage, UserUpdates, ...).
+type goTableData struct {
+	GeneratedAt string
+	Package     string
+	Name        string // table name
+	Type        string // model struct
+	Label       string // lower-case name used in error messages
+	Plural      string // DB accessor, e.g. db.Users()
+	Client      string
+	MapClient   string
+	Page        string
+	MapPage     string
+	PageIter    string
+	MapPageIter string
+	Repository  string
+	Updates     string
+	FieldType   string
+	Conditions  string
+	Where       string
+	ID          goIDData
+	Fields      []goFieldData
+	Resolvers   []goFieldData
+	Shapes      []goShapeData
+	Indexes     []goIndexData
+}
+
+// goIDData describes the primary key used by FindByID and DeleteByID(s).
+type goIDData struct {
+	Name   string // attribute name
+	Type   string // Go type
+	Param  string // parameter name
+	Params string // slice parameter name
+	Empty  string // condition rejecting a missing id; empty when every value is valid
+	Arg    string // id as the string the core client expects
+}
+
+// goFieldData is a struct field of a model, shape or resolver.
+type goFieldData struct {
+	Name     string
+	Export   string
+	GoType   string // struct field type
+	BaseType string // non-pointer scalar type used by condition builders
+	Ordered  bool   // supports Gt/Gte/Lt/Lte/Between
+	Text     bool   // supports Like/Contains/StartsWith
+}
+
+// goShapeData is a nested struct for an embedded object.
+type goShapeData struct {
+	Type   string
+	Path   string
+	Fields []goFieldData
+}
+
+type goIndexData struct {
+	Name   string
+	Export string
+	Param  string
+	GoType string
+	Unique bool
+}
+
+func newGoCommonData(tables []Table, resolvers map[string][]string, pkg, now string) goCommonData {
+	data := goCommonData{GeneratedAt: now, Package: pkg}
+	for _, t := range tables {
+		data.Tables = append(data.Tables, goTableName{Type: exportName(t.Name), Name: t.Name})
+	}
+	for table, names := range resolvers {
+		data.Resolvers = append(data.Resolvers, goTableResolvers{Table: table, Names: names})
+	}
+	sort.Slice(data.Resolvers, func(i, j int) bool { return data.Resolvers[i].Table < data.Resolvers[j].Table })
+	return data
+}
+
+func newGoTableData(table Table, pkg, now string, pointerFields bool) goTableData {
+	typeName := exportName(table.Name)
+	plural := typeName + "s"
+	data := goTableData{
+		GeneratedAt: now,
+		Package:     pkg,
+		Name:        table.Name,
+		Type:        typeName,
+		Label:       strings.ToLower(typeName),
+		Plural:      plural,
+		Client:      plural + "Client",
+		MapClient:   plural + "MapClient",
+		Page:        typeName + "Page",
+		MapPage:     typeName + "MapPage",
+		PageIter:    plural + "PageIterator",
+		MapPageIter: plural + "MapPageIterator",
+		Repository:  typeName + "Repository",
+		Updates:     typeName + "Updates",
+		FieldType:   typeName + "Field",
+		Conditions:  typeName + "Conditions",
+		Where:       typeName + "Where",
+	}
+
+	id := table.Identifier
+	if id.Name == "" {
+		id = Field{Name: "id", Type: "String"}
+	}
+	data.ID = goIDData{Name: id.Name, Type: mapGoType(id.Type, false, false), Param: goParamName(id.Name)}
+	data.ID.Params = data.ID.Param + "s"
+	// numeric ids accept any value, so they have no empty guard
+	switch data.ID.Type {
+	case "string":
+		data.ID.Empty = data.ID.Param + ` == ""`
+	case "any":
+		data.ID.Empty = data.ID.Param + " == nil"
+	case "time.Time":
+		data.ID.Empty = data.ID.Param + ".IsZero()"
+	}
+	data.ID.Arg = data.ID.Param
+	if data.ID.Type != "string" {
+		data.ID.Arg = "fmt.Sprint(" + data.ID.Param + ")"
+	}
+
+	for _, f := range table.Fields {
+		base := mapGoType(f.Type, false, false)
+		data.Fields = append(data.Fields, goFieldData{
+			Name:     f.Name,
+			Export:   exportName(f.Name),
+			GoType:   goFieldType(typeName, f, pointerFields),
+			BaseType: base,
+			Ordered:  base == "string" || base == "int64" || base == "float64" || base == "time.Time",
+			Text:     base == "string",
+		})
+		if f.Shape == nil {
+			continue
+		}
+		eachShapeType(shapeTypeName(typeName, f.Name), typeName+"."+f.Name, *f.Shape, func(name, path string, fields []ShapeField) {
+			shape := goShapeData{Type: name, Path: path}
+			for _, sf := range fields {
+				shape.Fields = append(shape.Fields, goFieldData{Name: sf.Name, Export: exportName(sf.Name), GoType: goShapeType(shapeTypeName(name, sf.Name), sf)})
+			}
+			data.Shapes = append(data.Shapes, shape)
+		})
+	}
+
+	for _, r := range table.Resolvers {
+		goType := "any"
+		if r.Target != "" {
+			goType = "*" + exportName(r.Target)
+			if r.Many {
+				goType = "[]" + exportName(r.Target)
+			}
+		}
+		data.Resolvers = append(data.Resolvers, goFieldData{Name: r.Name, Export: exportName(r.Name), GoType: goType})
+	}
+
+	for _, idx := range table.Indexes {
+		data.Indexes = append(data.Indexes, goIndexData{
+			Name:   idx.Field.Name,
+			Export: exportName(idx.Field.Name),
+			Param:  goParamName(idx.Field.Name),
+			GoType: mapGoType(idx.Field.Type, false, false),
+			Unique: idx.Unique,
+		})
+	}
+	return data
+}
+
+// goFieldType is the struct field type of an attribute; shaped embedded objects get nested structs.
+func goFieldType(typeName string, f Field, pointerFields bool) string {
+	if f.Shape == nil {
+		return mapGoType(f.Type, f.IsNullable, pointerFields)
+	}
+	if !f.Shape.IsObject() && !f.Shape.List {
+		return mapGoType(f.Shape.Type, f.IsNullable, pointerFields)
+	}
+	return goShapeType(shapeTypeName(typeName, f.Name), *f.Shape)
+}
@@ -22,6 +22,10 @@ func RenderJavaTypes(schemaJSON []byte, pkg string, outDir string, overwrite boo
 	if len(tables) == 0 {
 		return fmt.Errorf("schema has no tables")
 	}
+	shapes, err := embeddedShapes(schemaJSON)
+	if err != nil {
+		return err
+	}
 
 	pkgPath := strings.ReplaceAll(strings.TrimSpace(pkg), ".", string(filepath.Separator))
 	baseDir := outDir
@@ -43,7 +47,7 @@ func RenderJavaTypes(schemaJSON []byte, pkg string, outDir string, overwrite boo
 				return fmt.Errorf("%s exists (use --overwrite)", filename)
 			}
 		}
-		content := renderJavaClass(ent.Name, ent.Attributes, ent.Resolvers, pkg)
+		content := renderJavaClass(ent.Name, ent.Attributes, ent.Resolvers, shapes[ent.Name], pkg)
 		if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
 			return fmt.Errorf("write %s: %w", filename, err)
 		}
@@ -56,8 +60,10 @@ func renderJavaClass(name string, attrs []struct {
 	Type       string `json:"type"`
 	IsNullable bool   `json:"isNullable"`
 }, resolvers []struct {
-	Name string `json:"name"`
-}, pkg string) string {
+	Name     string `json:"name"`
+	Resolver string `json:"resolver"`
+	Returns  string `json:"returns"`
+}, shapes map[string]ShapeField, pkg string) string {
 	var b strings.Builder
 	b.WriteString("// Code generated by onyx gen --java; DO NOT EDIT.\n\n")
 	if strings.TrimSpace(pkg) != "" {
@@ -65,22 +71,31 @@ func renderJavaClass(name string, attrs []struct {
 		b.WriteString(pkg)
 		b.WriteString(";\n\n")
 	}
-	importDate := false
+	importDate, importList := false, false
 	for _, a := range attrs {
 		if isJavaTimeType(a.Type) {
 			importDate = true
-			break
 		}
 	}
+	for _, s := range shapes {
+		importDate = importDate || shapeUses(s, func(f ShapeField) bool { return !f.IsObject() && isJavaTimeType(f.Type) })
+		importList = importList || shapeUses(s, func(f ShapeField) bool { return f.List })
+	}
 	if importDate {
 		b.WriteString("import java.util.Date;\n")
 	}
+	if importList {
+		b.WriteString("import java.util.List;\n")
+	}
 	b.WriteString("\n")
 	b.WriteString("public class ")
 	b.WriteString(name)
 	b.WriteString(" {\n")
 	for _, a := range attrs {
 		jt := mapJavaType(a.Type)
+		if s, ok := shapes[a.Name]; ok {
+			jt = javaShapeType(shapeTypeName(name, a.Name), s)
+		}
 		b.WriteString("  public ")
 		b.WriteString(jt)
 		b.WriteString(" ")
@@ -98,10 +113,28 @@ func renderJavaClass(name string, attrs []struct {
 	b.WriteString("\n  public ")
 	b.WriteString(name)
 	b.WriteString("() {}\n")
+	for _, a := range attrs {
+		s, ok := shapes[a.Name]
+		if !ok {
+			continue
+		}
+		eachShapeType(shapeTypeName(name, a.Name), name+"."+a.Name, s, func(typeName, path string, fields []ShapeField) {
+			b.WriteString("\n  /** Shape of " + path + ". */\n")
+			b.WriteString("  public static class " + typeName + " {\n")
+			for _, f := range fields {
+				b.WriteString("    public " + javaShapeType(shapeTypeName(typeName, f.Name), f) + " " + f.Name + ";\n")
+			}
+			b.WriteString("\n    public " + typeName + "() {}\n  }\n")
+		})
+	}
 	b.WriteString("}\n")
 	return b.String()
 }
 
+func javaShapeType(typeName string, s ShapeField) string {
+	return shapeType(typeName, s, mapJavaType, func(t string) string { return "List<" + t + ">" })
+}
+
 func mapJavaType(schemaType string) string {
 	t := strings.ToLower(strings.TrimSpace(schemaType))
 	switch t {
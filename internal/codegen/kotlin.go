@@ -20,6 +20,10 @@ func RenderKotlinTypes(schemaJSON []byte, pkg string) (string, error) {
 	if len(tables) == 0 {
 		return "", fmt.Errorf("schema has no tables")
 	}
+	shapes, err := embeddedShapes(schemaJSON)
+	if err != nil {
+		return "", err
+	}
 
 	var b strings.Builder
 	b.WriteString("// Code generated by onyx gen --kotlin; DO NOT EDIT.\n\n")
@@ -39,6 +43,11 @@ func RenderKotlinTypes(schemaJSON []byte, pkg string) (string, error) {
 			}
 		}
 	}
+	for _, attrs := range shapes {
+		for _, s := range attrs {
+			needsDate = needsDate || shapeUses(s, func(f ShapeField) bool { return !f.IsObject() && isTimeType(f.Type) })
+		}
+	}
 	if needsDate {
 		b.WriteString("import java.util.Date\n\n")
 	}
@@ -47,11 +56,29 @@ func RenderKotlinTypes(schemaJSON []byte, pkg string) (string, error) {
 		if ent.Name == "" {
 			continue
 		}
+		for _, attr := range ent.Attributes {
+			if s, ok := shapes[ent.Name][attr.Name]; ok {
+				eachShapeType(shapeTypeName(ent.Name, attr.Name), ent.Name+"."+attr.Name, s, func(name, path string, fields []ShapeField) {
+					b.WriteString("/** Shape of " + path + ". */\n")
+					b.WriteString("data class " + name + "(\n")
+					for _, f := range fields {
+						b.WriteString("  val " + f.Name + ": " + kotlinShapeType(shapeTypeName(name, f.Name), f) + "? = null,\n")
+					}
+					b.WriteString(")\n\n")
+				})
+			}
+		}
 		b.WriteString("data class ")
 		b.WriteString(ent.Name)
 		b.WriteString("(\n")
 		for _, attr := range ent.Attributes {
 			kt := mapKotlinType(attr.Type, attr.IsNullable)
+			if s, ok := shapes[ent.Name][attr.Name]; ok {
+				kt = kotlinShapeType(shapeTypeName(ent.Name, attr.Name), s)
+				if attr.IsNullable {
+					kt += "?"
+				}
+			}
 			b.WriteString("  val ")
 			b.WriteString(attr.Name)
 			b.WriteString(": ")
@@ -93,6 +120,11 @@ func RenderKotlinTypes(schemaJSON []byte, pkg string) (string, error) {
 	return b.String(), nil
 }
 
+func kotlinShapeType(typeName string, s ShapeField) string {
+	scalar := func(t string) string { return mapKotlinType(t, false) }
+	return shapeType(typeName, s, scalar, func(t string) string { return "List<" + t + ">" })
+}
+
 func mapKotlinType(schemaType string, nullable bool) string {
 	t := strings.ToLower(strings.TrimSpace(schemaType))
 	var base string
@@ -22,6 +22,10 @@ func RenderPython(schemaJSON []byte, outDir string, overwrite bool) error {
 	if len(tables) == 0 {
 		return errors.New("schema has no tables")
 	}
+	shapes, err := embeddedShapes(schemaJSON)
+	if err != nil {
+		return err
+	}
 
 	if err := os.MkdirAll(outDir, 0o755); err != nil {
 		return err
@@ -29,18 +33,46 @@ func RenderPython(schemaJSON []byte, outDir string, overwrite bool) error {
 
 	// models.py
 	var models strings.Builder
-	models.WriteString("import datetime\nfrom typing import Any, Optional\n\n")
+	typingImports := "Any, Optional"
+	for _, attrs := range shapes {
+		for _, s := range attrs {
+			if shapeUses(s, func(f ShapeField) bool { return f.List }) {
+				typingImports = "Any, List, Optional"
+			}
+		}
+	}
+	models.WriteString("import datetime\nfrom typing import " + typingImports + "\n\n")
 	for _, ent := range tables {
 		if ent.Name == "" {
 			continue
 		}
+		ordered := OrderAttributes(ent.Identifier.Name, ent.Attributes)
+		for _, attr := range ordered {
+			if s, ok := shapes[ent.Name][attr.Name]; ok {
+				eachShapeType(shapeTypeName(ent.Name, attr.Name), ent.Name+"."+attr.Name, s, func(name, path string, fields []ShapeField) {
+					models.WriteString("class " + name + ":\n    \"\"\"Shape of " + path + ".\"\"\"\n    def __init__(self")
+					for _, f := range fields {
+						models.WriteString(fmt.Sprintf(", %s: Optional[%s] = None", f.Name, pyShapeType(shapeTypeName(name, f.Name), f)))
+					}
+					models.WriteString(", **extra: Any):\n")
+					for _, f := range fields {
+						models.WriteString(fmt.Sprintf("        self.%s = %s\n", f.Name, f.Name))
+					}
+					models.WriteString("        for k, v in extra.items():\n")
+					models.WriteString("            setattr(self, k, v)\n\n\n")
+				})
+			}
+		}
+
 		models.WriteString("class ")
 		models.WriteString(ent.Name)
 		models.WriteString(":\n    \"\"\"Generated model (plain Python class). Resolver/extra fields are allowed via **extra.\"\"\"\n    def __init__(self")
 
-		ordered := OrderAttributes(ent.Identifier.Name, ent.Attributes)
 		for _, attr := range ordered {
 			pyType, optional := mapPyType(attr.Type, attr.IsNullable)
+			if s, ok := shapes[ent.Name][attr.Name]; ok {
+				pyType = pyShapeType(shapeTypeName(ent.Name, attr.Name), s)
+			}
 			if optional {
 				models.WriteString(fmt.Sprintf(", %s: Optional[%s] = None", attr.Name, pyType))
 			} else {
@@ -165,6 +197,14 @@ func RenderPython(schemaJSON []byte, outDir string, overwrite bool) error {
 	return nil
 }
 
+func pyShapeType(typeName string, s ShapeField) string {
+	scalar := func(t string) string {
+		base, _ := mapPyType(t, false)
+		return base
+	}
+	return shapeType(typeName, s, scalar, func(t string) string { return "List[" + t + "]" })
+}
+
 func mapPyType(schemaType string, nullable bool) (string, bool) {
 	t := strings.ToLower(strings.TrimSpace(schemaType))
 	var base string
@@ -11,29 +11,47 @@ type SchemaParsed struct {
 	Tables []struct {
 		Name       string `json:"name"`
 		Identifier struct {
-			Name string `json:"name"`
+			Name      string `json:"name"`
+			Type      string `json:"type"`
+			Generator string `json:"generator"`
 		} `json:"identifier"`
 		Attributes []struct {
 			Name       string `json:"name"`
 			Type       string `json:"type"`
 			IsNullable bool   `json:"isNullable"`
 		} `json:"attributes"`
+		Indexes []struct {
+			Name   string `json:"name"`
+			Type   string `json:"type"`
+			Unique bool   `json:"unique"`
+		} `json:"indexes"`
 		Resolvers []struct {
-			Name string `json:"name"`
+			Name     string `json:"name"`
+			Resolver string `json:"resolver"`
+			Returns  string `json:"returns"`
 		} `json:"resolvers"`
 	} `json:"tables"`
 	Entities []struct {
 		Name       string `json:"name"`
 		Identifier struct {
-			Name string `json:"name"`
+			Name      string `json:"name"`
+			Type      string `json:"type"`
+			Generator string `json:"generator"`
 		} `json:"identifier"`
 		Attributes []struct {
 			Name       string `json:"name"`
 			Type       string `json:"type"`
 			IsNullable bool   `json:"isNullable"`
 		} `json:"attributes"`
+		Indexes []struct {
+			Name   string `json:"name"`
+			Type   string `json:"type"`
+			Unique bool   `json:"unique"`
+		} `json:"indexes"`
 		Resolvers []struct {
-			Name string `json:"name"`
+			Name     string `json:"name"`
+			Resolver string `json:"resolver"`
+			Returns  string `json:"returns"`
 		} `json:"resolvers"`
 	} `json:"entities"`
 }
@@ -51,6 +69,10 @@ func RenderTypescriptTypes(schemaJSON []byte, typeName string) (string, error) {
 	if len(tables) == 0 {
 		return "", errors.New("schema has no tables")
 	}
+	shapes, err := embeddedShapes(schemaJSON)
+	if err != nil {
+		return "", err
+	}
 
 	var b strings.Builder
 	b.WriteString("// AUTO-GENERATED BY onyx-gen. DO NOT EDIT.\n\n")
@@ -59,13 +81,32 @@ func RenderTypescriptTypes(schemaJSON []byte, typeName string) (string, error) {
 		if ent.Name == "" {
 			continue
 		}
+		ordered := OrderAttributes(ent.Identifier.Name, ent.Attributes)
+		for _, attr := range ordered {
+			if s, ok := shapes[ent.Name][attr.Name]; ok {
+				eachShapeType(shapeTypeName(ent.Name, attr.Name), ent.Name+"."+attr.Name, s, func(name, path string, fields []ShapeField) {
+					b.WriteString("/** Shape of " + path + ". */\n")
+					b.WriteString("export interface " + name + " {\n")
+					for _, f := range fields {
+						b.WriteString("  " + f.Name + "?: " + tsShapeType(shapeTypeName(name, f.Name), f) + ";\n")
+					}
+					b.WriteString("}\n\n")
+				})
+			}
+		}
+
 		b.WriteString("export interface ")
 		b.WriteString(ent.Name)
 		b.WriteString(" {\n")
 
-		ordered := OrderAttributes(ent.Identifier.Name, ent.Attributes)
 		for _, attr := range ordered {
 			tsType := mapTSType(attr.Type, attr.IsNullable)
+			if s, ok := shapes[ent.Name][attr.Name]; ok {
+				tsType = tsShapeType(shapeTypeName(ent.Name, attr.Name), s)
+				if attr.IsNullable {
+					tsType += " | null"
+				}
+			}
 			b.WriteString("  ")
 			b.WriteString(attr.Name)
 			b.WriteString("?: ")
@@ -114,6 +155,10 @@ func RenderTypescriptTypes(schemaJSON []byte, typeName string) (string, error) {
 	return b.String(), nil
 }
 
+func tsShapeType(typeName string, s ShapeField) string {
+	return shapeType(typeName, s, func(t string) string { return mapTSType(t, false) }, func(t string) string { return t + "[]" })
+}
+
 func mapTSType(schemaType string, nullable bool) string {
 	t := strings.ToLower(strings.TrimSpace(schemaType))
 	var base string
@@ -0,0 +1,338 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Keys are the config file fields editable with `onyx config`, in display order.
+var Keys = []string{"databaseId", "baseUrl", "apiKey", "apiSecret", "aiBaseUrl", "defaultModel", "codegenLanguage", "credentialHelper"}
+
+// Scope names a config file location from the search chain.
+type Scope string
+
+const (
+	ScopeProject Scope = "project" // ./onyx-database.json
+	ScopeConfig  Scope = "config"  // ./config/onyx-database.json
+	ScopeUser    Scope = "user"    // ~/.onyx/onyx-database.json
+)
+
+// ScopePath returns the config file for scope, relative to wd for project scopes.
+func ScopePath(scope Scope, wd string) (string, error) {
+	switch scope {
+	case ScopeProject:
+		return filepath.Join(wd, "onyx-database.json"), nil
+	case ScopeConfig:
+		return filepath.Join(wd, "config", "onyx-database.json"), nil
+	case ScopeUser:
+		return expandPath(filepath.Join("~", ".onyx", "onyx-database.json")), nil
+	default:
+		return "", fmt.Errorf("unknown scope %q (expected project, config or user)", scope)
+	}
+}
+
+// SearchPaths returns the config files Resolve tries, in order, when no explicit path is set.
+func SearchPaths(wd, databaseID string) []string {
+	return configSearchOrder(wd, databaseID)
+}
+
+// IsSecretKey reports whether values of key should be masked on display.
+func IsSecretKey(key string) bool {
+	return key == "apiSecret"
+}
+
+// CheckKey returns an error for keys `onyx config` does not manage.
+func CheckKey(key string) error {
+	for _, k := range Keys {
+		if k == key {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown config key %q (expected one of %s)", key, strings.Join(Keys, ", "))
+}
+
+// Lookup returns the resolved value for a config key.
+func (rc ResolvedConfig) Lookup(key string) (ResolvedValue, bool) {
+	switch key {
+	case "databaseId":
+		return rc.DatabaseID, true
+	case "baseUrl":
+		return rc.BaseURL, true
+	case "apiKey":
+		return rc.APIKey, true
+	case "apiSecret":
+		return rc.APISecret, true
+	case "aiBaseUrl":
+		return rc.AIBaseURL, true
+	case "defaultModel":
+		return rc.DefaultModel, true
+	case "codegenLanguage":
+		return rc.CodegenLang, true
+	case "credentialHelper":
+		return rc.CredentialHelper, true
+	}
+	return ResolvedValue{}, false
+}
+
+// ReadFileValues returns the managed keys set in path, or in its profiles.<profile> entry.
+// A missing file has no values.
+func ReadFileValues(path, profile string) (map[string]string, error) {
+	doc, err := readConfigDoc(path)
+	if err != nil {
+		return nil, err
+	}
+	section, err := configSection(doc, profile, false)
+	if err != nil || section == nil {
+		return map[string]string{}, err
+	}
+	values := map[string]string{}
+	for _, k := range Keys {
+		if v, ok := section.values[k].(string); ok {
+			values[k] = v
+		}
+	}
+	return values, nil
+}
+
+// SetFileValue writes key=value into path (or its profiles.<profile> entry), keeping other keys
+// and their order. credentialHelper is refused outside user-scope files, where Resolve ignores it.
+func SetFileValue(path, profile, key, value string) error {
+	if err := CheckKey(key); err != nil {
+		return err
+	}
+	if key == "credentialHelper" && !isUserScopePath(path) {
+		return fmt.Errorf("credentialHelper is only read from user config files (under ~/.onyx), not %s; use --scope user", path)
+	}
+	doc, err := readConfigDoc(path)
+	if err != nil {
+		return err
+	}
+	section, err := configSection(doc, profile, true)
+	if err != nil {
+		return err
+	}
+	section.set(key, value)
+	return writeConfigDoc(path, doc)
+}
+
+// UnsetFileValue removes key from path (or its profiles.<profile> entry) and reports whether it was set.
+func UnsetFileValue(path, profile, key string) (bool, error) {
+	if err := CheckKey(key); err != nil {
+		return false, err
+	}
+	doc, err := readConfigDoc(path)
+	if err != nil {
+		return false, err
+	}
+	section, err := configSection(doc, profile, false)
+	if err != nil || section == nil {
+		return false, err
+	}
+	if _, ok := section.values[key]; !ok {
+		return false, nil
+	}
+	section.delete(key)
+	return true, writeConfigDoc(path, doc)
+}
+
+// configObject is a JSON object that keeps its key order, so editing one key leaves the rest of a
+// hand-written config file as it was. Nested objects are *configObject.
+type configObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func newConfigObject() *configObject {
+	return &configObject{values: map[string]any{}}
+}
+
+func (o *configObject) set(key string, value any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+func (o *configObject) delete(key string) {
+	delete(o.values, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+func (o *configObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(o.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// decodeConfigValue reads the next JSON value from dec, keeping object key order.
+func decodeConfigValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := newConfigObject()
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, _ := keyTok.(string)
+			value, err := decodeConfigValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj.set(key, value)
+		}
+		_, err := dec.Token() // }
+		return obj, err
+	case json.Delim('['):
+		list := []any{}
+		for dec.More() {
+			value, err := decodeConfigValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err := dec.Token() // ]
+		return list, err
+	}
+	return tok, nil
+}
+
+func readConfigDoc(path string) (*configObject, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return newConfigObject(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return newConfigObject(), nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := decodeConfigValue(dec)
+	if err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("parse config %s: unexpected data after the top-level object", path)
+	}
+	doc, ok := v.(*configObject)
+	if !ok {
+		return nil, fmt.Errorf("parse config %s: not a JSON object", path)
+	}
+	return doc, nil
+}
+
+// configSection returns doc itself, or doc.profiles[profile] (created when create is set).
+func configSection(doc *configObject, profile string, create bool) (*configObject, error) {
+	if profile == "" {
+		return doc, nil
+	}
+	profiles, ok := doc.values["profiles"].(*configObject)
+	if !ok {
+		if _, exists := doc.values["profiles"]; exists {
+			return nil, errors.New(`"profiles" is not an object`)
+		}
+		if !create {
+			return nil, nil
+		}
+		profiles = newConfigObject()
+		doc.set("profiles", profiles)
+	}
+	section, ok := profiles.values[profile].(*configObject)
+	if !ok {
+		if _, exists := profiles.values[profile]; exists {
+			return nil, fmt.Errorf("profile %q is not an object", profile)
+		}
+		if !create {
+			return nil, nil
+		}
+		section = newConfigObject()
+		profiles.set(profile, section)
+	}
+	return section, nil
+}
+
+func writeConfigDoc(path string, doc *configObject) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// config files hold secrets: keep new files private
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// Candidate statuses reported by InspectSearchPaths.
+const (
+	CandidateFound      = "found"
+	CandidateNotFound   = "not-found"
+	CandidateParseError = "parse-error"
+	CandidateUnreadable = "unreadable"
+)
+
+// Candidate is one config search path and what was found there.
+type Candidate struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// InspectSearchPaths reports the status of every path in SearchPaths(wd, databaseID).
+func InspectSearchPaths(wd, databaseID string) []Candidate {
+	paths := SearchPaths(wd, databaseID)
+	out := make([]Candidate, 0, len(paths))
+	for _, path := range paths {
+		c := Candidate{Path: path, Status: CandidateFound}
+		data, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			c.Status = CandidateNotFound
+		case err != nil:
+			c.Status, c.Error = CandidateUnreadable, err.Error()
+		default:
+			var fc fileConfig
+			if err := json.Unmarshal(data, &fc); err != nil {
+				c.Status, c.Error = CandidateParseError, err.Error()
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetUnsetFileValue_KeepsOtherKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", "onyx-database.json")
+	writeFile(t, path, `{"databaseId": "db", "custom": {"keep": true}}`)
+
+	if err := SetFileValue(path, "", "apiSecret", "s3cret"); err != nil {
+		t.Fatalf("SetFileValue: %v", err)
+	}
+	if err := SetFileValue(path, "staging", "databaseId", "stg"); err != nil {
+		t.Fatalf("SetFileValue profile: %v", err)
+	}
+	raw, _ := os.ReadFile(path)
+	if !strings.Contains(string(raw), `"keep": true`) {
+		t.Fatalf("unmanaged keys were dropped:\n%s", raw)
+	}
+
+	values, err := ReadFileValues(path, "")
+	if err != nil || values["databaseId"] != "db" || values["apiSecret"] != "s3cret" {
+		t.Fatalf("top-level values = %v, err=%v", values, err)
+	}
+	values, err = ReadFileValues(path, "staging")
+	if err != nil || len(values) != 1 || values["databaseId"] != "stg" {
+		t.Fatalf("profile values = %v, err=%v", values, err)
+	}
+
+	if removed, err := UnsetFileValue(path, "", "apiSecret"); err != nil || !removed {
+		t.Fatalf("UnsetFileValue: removed=%v err=%v", removed, err)
+	}
+	if removed, _ := UnsetFileValue(path, "", "apiSecret"); removed {
+		t.Fatalf("second unset should report nothing removed")
+	}
+	if err := SetFileValue(path, "", "apiToken", "x"); err == nil {
+		t.Fatalf("expected error for unknown key")
+	}
+}
+
+func TestSetUnsetFileValue_KeepsKeyOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "onyx-database.json")
+	writeFile(t, path, `{"zeta": 1, "databaseId": "db", "apiKey": "k",
+  "profiles": {"prod": {"databaseId": "p"}, "dev": {"databaseId": "d"}}}`)
+
+	if err := SetFileValue(path, "", "databaseId", "db2"); err != nil {
+		t.Fatalf("SetFileValue: %v", err)
+	}
+	if err := SetFileValue(path, "dev", "apiKey", "dk"); err != nil {
+		t.Fatalf("SetFileValue profile: %v", err)
+	}
+	if _, err := UnsetFileValue(path, "", "apiKey"); err != nil {
+		t.Fatalf("UnsetFileValue: %v", err)
+	}
+	raw, _ := os.ReadFile(path)
+	want := `{
+  "zeta": 1,
+  "databaseId": "db2",
+  "profiles": {
+    "prod": {
+      "databaseId": "p"
+    },
+    "dev": {
+      "databaseId": "d",
+      "apiKey": "dk"
+    }
+  }
+}
+`
+	if string(raw) != want {
+		t.Fatalf("got\n%s\nwant\n%s", raw, want)
+	}
+}
+
+func TestSetFileValue_CredentialHelperOnlyInUserScope(t *testing.T) {
+	home, wd := isolate(t)
+	project := filepath.Join(wd, "onyx-database.json")
+	if err := SetFileValue(project, "", "credentialHelper", "helper"); err == nil {
+		t.Fatal("expected credentialHelper to be refused in a project config file")
+	}
+	if _, err := os.Stat(project); err == nil {
+		t.Fatal("refused credentialHelper should not create the file")
+	}
+	user := filepath.Join(home, ".onyx", "onyx-database.json")
+	if err := SetFileValue(user, "ci", "credentialHelper", "helper"); err != nil {
+		t.Fatalf("SetFileValue in user scope: %v", err)
+	}
+}
+
+func TestScopePath(t *testing.T) {
+	home, wd := isolate(t)
+	for scope, want := range map[Scope]string{
+		ScopeProject: filepath.Join(wd, "onyx-database.json"),
+		ScopeConfig:  filepath.Join(wd, "config", "onyx-database.json"),
+		ScopeUser:    filepath.Join(home, ".onyx", "onyx-database.json"),
+	} {
+		if got, err := ScopePath(scope, wd); err != nil || got != want {
+			t.Fatalf("ScopePath(%s) = %q, %v; want %q", scope, got, err, want)
+		}
+	}
+	if _, err := ScopePath("global", wd); err == nil {
+		t.Fatalf("expected error for unknown scope")
+	}
+}
+
+func TestInspectSearchPaths(t *testing.T) {
+	_, wd := isolate(t)
+	writeFile(t, filepath.Join(wd, "onyx-database.json"), `{"databaseId": "db"}`)
+	writeFile(t, filepath.Join(wd, "config", "onyx-database.json"), `{"databaseId": `)
+
+	status := map[string]string{}
+	for _, c := range InspectSearchPaths(wd, "") {
+		status[c.Path] = c.Status
+	}
+	if got := status[filepath.Join(wd, "onyx-database.json")]; got != CandidateFound {
+		t.Fatalf("project file status = %q", got)
+	}
+	if got := status[filepath.Join(wd, "config", "onyx-database.json")]; got != CandidateParseError {
+		t.Fatalf("config dir file status = %q", got)
+	}
+	if len(status) < 3 {
+		t.Fatalf("expected home candidates too, got %v", status)
+	}
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// runCredentialHelper runs `<helper> get` using the git-credential protocol: known attributes are
+// written to stdin as key=value lines followed by a blank line, and key=value lines are read back
+// from stdout (apiKey, apiSecret). Stderr is passed through so helpers can prompt.
+func runCredentialHelper(helper string, attrs [][2]string) (map[string]string, error) {
+	fields := strings.Fields(helper)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("credentialHelper is empty")
+	}
+	var stdin bytes.Buffer
+	for _, kv := range attrs {
+		if kv[1] != "" {
+			fmt.Fprintf(&stdin, "%s=%s\n", kv[0], kv[1])
+		}
+	}
+	stdin.WriteString("\n")
+
+	cmd := exec.Command(fields[0], append(fields[1:], "get")...)
+	cmd.Stdin = &stdin
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("credential helper %q: %w", helper, err)
+	}
+
+	values := map[string]string{}
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if line == "" {
+			break
+		}
+		if k, v, ok := strings.Cut(line, "="); ok {
+			values[k] = v
+		}
+	}
+	return values, sc.Err()
+}
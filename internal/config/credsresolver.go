@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -34,12 +35,16 @@ type ResolvedConfig struct {
 	DefaultModel ResolvedValue
 	ConfigFile   string // path actually used (if any)
 	CodegenLang  ResolvedValue
+	Profile      ResolvedValue // selected profile name (if any)
+
+	CredentialHelper ResolvedValue // command used for missing apiKey/apiSecret (if any)
 }
 
 type envValues struct {
 	ResolvedConfig
 	ConfigPath  ResolvedValue
 	CodegenLang ResolvedValue
+	Profile     ResolvedValue
 }
 
 // Options allows callers to provide explicit values/paths that outrank env/config files.
@@ -51,6 +56,7 @@ type Options struct {
 	AIBaseURL    string
 	DefaultModel string
 	ConfigPath   string // optional explicit config file path
+	Profile      string // optional profile name (see ProfilesPath)
 	WorkDir      string // defaults to current working directory if empty
 }
 
@@ -62,10 +68,17 @@ type fileConfig struct {
 	AIBaseURL    string `json:"aiBaseUrl"`
 	DefaultModel string `json:"defaultModel"`
 	CodegenLang  string `json:"codegenLanguage"`
+
+	CredentialHelper string                `json:"credentialHelper"`
+	Profiles         map[string]fileConfig `json:"profiles,omitempty"`
 }
 
 // Resolve merges configuration following the canonical chain:
-// explicit options -> environment -> ONYX_CONFIG_PATH file -> project config files -> home profile.
+// explicit options -> environment -> selected profile -> ONYX_CONFIG_PATH file -> project config files -> home profile.
+// A profile (--profile / ONYX_PROFILE) is looked up in the "profiles" key of the config file in use,
+// then in ProfilesPath(); its values report Source "profile:<name>".
+// Resolve does not run the credentialHelper or read the credential store; callers that need
+// credentials call FillCredentials on the result.
 func Resolve(opts Options) (ResolvedConfig, error) {
 	wd := opts.WorkDir
 	if wd == "" {
@@ -97,30 +110,50 @@ func Resolve(opts Options) (ResolvedConfig, error) {
 	fillIfEmpty(&cfg.AIBaseURL, env.AIBaseURL.Value, env.AIBaseURL.Source)
 	fillIfEmpty(&cfg.DefaultModel, env.DefaultModel.Value, env.DefaultModel.Source)
 	fillIfEmpty(&cfg.CodegenLang, env.CodegenLang.Value, env.CodegenLang.Source)
+	fillIfEmpty(&cfg.CredentialHelper, env.CredentialHelper.Value, env.CredentialHelper.Source)
 
 	// 3) Config files (explicit path -> ONYX_CONFIG_PATH -> search chain)
+	var fc fileConfig
 	configPath := firstNonEmpty(opts.ConfigPath, env.ConfigPath.Value)
 	if configPath != "" {
 		configPath = expandPath(configPath)
-		fc, err := loadFileConfig(configPath)
+		var err error
+		fc, err = loadFileConfig(configPath)
 		if err != nil {
 			return cfg, fmt.Errorf("read config %s: %w", configPath, err)
 		}
 		cfg.ConfigFile = configPath
-		mergeFileConfig(&cfg, fc, configPath)
-		return cfg, nil
+	} else {
+		// search standard locations
+		candidates := configSearchOrder(wd, cfg.DatabaseID.Value)
+		for _, path := range candidates {
+			found, err := loadFileConfig(path)
+			if err != nil {
+				continue
+			}
+			if !isUserScopePath(path) {
+				dropCredentialHelper(&found)
+			}
+			fc = found
+			cfg.ConfigFile = path
+			break
+		}
 	}
 
-	// search standard locations
-	candidates := configSearchOrder(wd, cfg.DatabaseID.Value)
-	for _, path := range candidates {
-		fc, err := loadFileConfig(path)
-		if err != nil {
-			continue
+	// a selected profile outranks the plain values of the config file it lives next to
+	cfg.Profile = resolveOne(opts.Profile, "flag")
+	fillIfEmpty(&cfg.Profile, env.Profile.Value, env.Profile.Source)
+	if cfg.Profile.Value != "" {
+		if err := applyProfile(&cfg, cfg.Profile.Value, fc); err != nil {
+			return cfg, err
 		}
-		cfg.ConfigFile = path
-		mergeFileConfig(&cfg, fc, path)
-		break
+	}
+	if cfg.ConfigFile != "" {
+		mergeFileConfig(&cfg, fc, "config:"+cfg.ConfigFile)
+	}
+
+	if configPath != "" {
+		return cfg, nil
 	}
 
 	// 4) Defaults (lowest precedence)
@@ -140,6 +173,32 @@ func Resolve(opts Options) (ResolvedConfig, error) {
 	return cfg, nil
 }
 
+// isUserScopePath reports whether a config file belongs to the user (~/.onyx or the legacy
+// ~/onyx-database.json) rather than to the project in the working directory.
+func isUserScopePath(path string) bool {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return false
+	}
+	path = filepath.Clean(path)
+	if path == filepath.Join(home, "onyx-database.json") {
+		return true
+	}
+	rel, err := filepath.Rel(filepath.Join(home, ".onyx"), path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// dropCredentialHelper clears credentialHelper from a project config file and its profiles. The
+// helper is a command the CLI runs, so a cloned repository must not be able to choose it; it is
+// only taken from user-scope files, an explicit --config/ONYX_CONFIG_PATH, or ONYX_CREDENTIAL_HELPER.
+func dropCredentialHelper(fc *fileConfig) {
+	fc.CredentialHelper = ""
+	for name, p := range fc.Profiles {
+		p.CredentialHelper = ""
+		fc.Profiles[name] = p
+	}
+}
+
 // loadEnv gathers env vars into ResolvedConfig form.
 func loadEnv() envValues {
 	return envValues{
@@ -151,9 +210,12 @@ func loadEnv() envValues {
 			AIBaseURL:    resolveOne(os.Getenv("ONYX_AI_BASE_URL"), "env"),
 			DefaultModel: resolveOne(os.Getenv("ONYX_DEFAULT_MODEL"), "env"),
 			ConfigFile:   "",
+
+			CredentialHelper: resolveOne(os.Getenv("ONYX_CREDENTIAL_HELPER"), "env"),
 		},
 		ConfigPath:  resolveOne(os.Getenv("ONYX_CONFIG_PATH"), "env"),
 		CodegenLang: resolveOne(os.Getenv("ONYX_CODEGEN_LANGUAGE"), "env"),
+		Profile:     resolveOne(os.Getenv("ONYX_PROFILE"), "env"),
 	}
 }
 
@@ -171,14 +233,97 @@ func fillIfEmpty(target *ResolvedValue, val, source string) {
 	}
 }
 
-func mergeFileConfig(cfg *ResolvedConfig, fc fileConfig, path string) {
-	fillIfEmpty(&cfg.DatabaseID, fc.DatabaseID, "config:"+path)
-	fillIfEmpty(&cfg.BaseURL, fc.BaseURL, "config:"+path)
-	fillIfEmpty(&cfg.APIKey, fc.APIKey, "config:"+path)
-	fillIfEmpty(&cfg.APISecret, fc.APISecret, "config:"+path)
-	fillIfEmpty(&cfg.AIBaseURL, fc.AIBaseURL, "config:"+path)
-	fillIfEmpty(&cfg.DefaultModel, fc.DefaultModel, "config:"+path)
-	fillIfEmpty(&cfg.CodegenLang, fc.CodegenLang, "config:"+path)
+func mergeFileConfig(cfg *ResolvedConfig, fc fileConfig, source string) {
+	fillIfEmpty(&cfg.DatabaseID, fc.DatabaseID, source)
+	fillIfEmpty(&cfg.BaseURL, fc.BaseURL, source)
+	fillIfEmpty(&cfg.APIKey, fc.APIKey, source)
+	fillIfEmpty(&cfg.APISecret, fc.APISecret, source)
+	fillIfEmpty(&cfg.AIBaseURL, fc.AIBaseURL, source)
+	fillIfEmpty(&cfg.DefaultModel, fc.DefaultModel, source)
+	fillIfEmpty(&cfg.CodegenLang, fc.CodegenLang, source)
+	fillIfEmpty(&cfg.CredentialHelper, fc.CredentialHelper, source)
+}
+
+// FillCredentials completes a missing apiKey/apiSecret from the credentialHelper command, if
+// configured, then from DefaultCredentialStore() by database ID. It runs after Resolve so the
+// helper sees the final baseUrl, and only for commands that need credentials, since the helper
+// may prompt or fail.
+func (cfg *ResolvedConfig) FillCredentials() error {
+	if cfg.APIKey.Value != "" && cfg.APISecret.Value != "" {
+		return nil
+	}
+	if helper := cfg.CredentialHelper.Value; helper != "" {
+		values, err := runCredentialHelper(helper, [][2]string{
+			{"databaseId", cfg.DatabaseID.Value},
+			{"baseUrl", cfg.BaseURL.Value},
+			{"apiKey", cfg.APIKey.Value},
+		})
+		if err != nil {
+			return err
+		}
+		fillIfEmpty(&cfg.APIKey, values["apiKey"], "credentialHelper")
+		fillIfEmpty(&cfg.APISecret, values["apiSecret"], "credentialHelper")
+		if cfg.APIKey.Value != "" && cfg.APISecret.Value != "" {
+			return nil
+		}
+	}
+	if cfg.DatabaseID.Value == "" {
+		return nil
+	}
+	store := DefaultCredentialStore()
+	c, ok, err := store.Get(cfg.DatabaseID.Value)
+	if err != nil || !ok {
+		return err
+	}
+	// never pair a stored secret with a different key resolved elsewhere
+	if cfg.APIKey.Value != "" && cfg.APIKey.Value != c.APIKey {
+		return nil
+	}
+	source := "credentials:" + store.Path
+	fillIfEmpty(&cfg.APIKey, c.APIKey, source)
+	fillIfEmpty(&cfg.APISecret, c.APISecret, source)
+	// the stored base URL outranks only the default
+	if c.BaseURL != "" && (cfg.BaseURL.Value == "" || cfg.BaseURL.Source == "default") {
+		cfg.BaseURL = ResolvedValue{Value: c.BaseURL, Source: source}
+	}
+	return nil
+}
+
+// ProfilesPath is the user-wide profiles file: {"profiles": {"<name>": {<config keys>}}}.
+func ProfilesPath() string {
+	return expandPath(filepath.Join("~", ".onyx", "profiles.json"))
+}
+
+// applyProfile merges the named profile from the active config file or ProfilesPath().
+func applyProfile(cfg *ResolvedConfig, name string, fc fileConfig) error {
+	profile, ok := fc.Profiles[name]
+	available := profileNames(fc.Profiles)
+	if !ok {
+		path := ProfilesPath()
+		home, err := loadFileConfig(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("read profiles %s: %w", path, err)
+		}
+		profile, ok = home.Profiles[name]
+		available = append(available, profileNames(home.Profiles)...)
+	}
+	if !ok {
+		if len(available) == 0 {
+			return fmt.Errorf("profile %q not found: no profiles defined in the config file or %s", name, ProfilesPath())
+		}
+		return fmt.Errorf("profile %q not found (available: %s)", name, strings.Join(available, ", "))
+	}
+	mergeFileConfig(cfg, profile, "profile:"+name)
+	return nil
+}
+
+func profileNames(profiles map[string]fileConfig) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 func loadFileConfig(path string) (fileConfig, error) {
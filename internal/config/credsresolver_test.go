@@ -0,0 +1,209 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// isolate clears ONYX_* env vars and points HOME at a temp dir.
+func isolate(t *testing.T) (home, wd string) {
+	t.Helper()
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, "ONYX_") {
+			key := kv[:strings.IndexByte(kv, '=')]
+			t.Setenv(key, "")
+		}
+	}
+	home = t.TempDir()
+	t.Setenv("HOME", home)
+	return home, t.TempDir()
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolve_ProfileFromProjectConfig(t *testing.T) {
+	_, wd := isolate(t)
+	writeFile(t, filepath.Join(wd, "onyx-database.json"), `{
+		"databaseId": "prod-db", "apiKey": "prod-key", "apiSecret": "prod-secret",
+		"profiles": {"staging": {"databaseId": "staging-db", "apiKey": "staging-key"}}
+	}`)
+
+	rc, err := Resolve(Options{WorkDir: wd, Profile: "staging"})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if rc.DatabaseID.Value != "staging-db" || rc.DatabaseID.Source != "profile:staging" {
+		t.Fatalf("databaseId = %+v, want staging-db from profile:staging", rc.DatabaseID)
+	}
+	if rc.APISecret.Value != "prod-secret" || !strings.HasPrefix(rc.APISecret.Source, "config:") {
+		t.Fatalf("apiSecret should fall back to the file's top-level value, got %+v", rc.APISecret)
+	}
+	if rc.Profile.Value != "staging" || rc.Profile.Source != "flag" {
+		t.Fatalf("profile = %+v", rc.Profile)
+	}
+}
+
+func TestResolve_ProfileFromHomeProfilesFile(t *testing.T) {
+	home, wd := isolate(t)
+	writeFile(t, filepath.Join(home, ".onyx", "profiles.json"), `{"profiles": {"dev": {"databaseId": "dev-db", "baseUrl": "http://localhost:8787"}}}`)
+	t.Setenv("ONYX_PROFILE", "dev")
+	t.Setenv("ONYX_DATABASE_ID", "env-db")
+
+	rc, err := Resolve(Options{WorkDir: wd})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if rc.DatabaseID.Value != "env-db" || rc.DatabaseID.Source != "env" {
+		t.Fatalf("env should outrank profile, got %+v", rc.DatabaseID)
+	}
+	if rc.BaseURL.Value != "http://localhost:8787" || rc.BaseURL.Source != "profile:dev" {
+		t.Fatalf("baseUrl = %+v", rc.BaseURL)
+	}
+	if rc.Profile.Source != "env" {
+		t.Fatalf("profile source = %q, want env", rc.Profile.Source)
+	}
+}
+
+func TestResolve_UnknownProfile(t *testing.T) {
+	home, wd := isolate(t)
+	writeFile(t, filepath.Join(home, ".onyx", "profiles.json"), `{"profiles": {"dev": {}, "prod": {}}}`)
+
+	_, err := Resolve(Options{WorkDir: wd, Profile: "qa"})
+	if err == nil || !strings.Contains(err.Error(), "available: dev, prod") {
+		t.Fatalf("err = %v, want unknown-profile error listing dev, prod", err)
+	}
+}
+
+func TestResolve_SecretFromCredentialStore(t *testing.T) {
+	_, wd := isolate(t)
+	store := DefaultCredentialStore()
+	if err := store.Put(Credential{DatabaseID: "db1", APIKey: "stored-key", APISecret: "stored-secret"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	raw, err := os.ReadFile(store.Path)
+	if err != nil || strings.Contains(string(raw), "stored-secret") {
+		t.Fatalf("credential store should be encrypted at rest (err=%v)", err)
+	}
+	for _, path := range []string{store.Path, store.KeyPath} {
+		if info, err := os.Stat(path); err != nil || (runtime.GOOS != "windows" && info.Mode().Perm() != 0o600) {
+			t.Fatalf("%s should be readable by the user only (info=%v err=%v)", path, info, err)
+		}
+	}
+
+	rc, err := Resolve(Options{WorkDir: wd, DatabaseID: "db1"})
+	if err == nil {
+		err = rc.FillCredentials()
+	}
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if rc.APISecret.Value != "stored-secret" || rc.APISecret.Source != "credentials:"+store.Path {
+		t.Fatalf("apiSecret = %+v", rc.APISecret)
+	}
+
+	// a key resolved elsewhere must not be paired with the stored secret
+	rc, err = Resolve(Options{WorkDir: wd, DatabaseID: "db1", APIKey: "other-key"})
+	if err == nil {
+		err = rc.FillCredentials()
+	}
+	if err != nil || rc.APISecret.Value != "" {
+		t.Fatalf("mismatched key picked up stored secret: %+v err=%v", rc.APISecret, err)
+	}
+
+	if removed, err := store.Delete("db1"); err != nil || !removed {
+		t.Fatalf("Delete: removed=%v err=%v", removed, err)
+	}
+}
+
+func TestResolve_SecretFromCredentialHelper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("helper script uses sh")
+	}
+	home, wd := isolate(t)
+	helper := filepath.Join(t.TempDir(), "helper.sh")
+	// echo the databaseId and baseUrl back so the test can see the request attributes arrived
+	writeFile(t, helper, "#!/bin/sh\n[ \"$1\" = get ] || exit 1\nwhile IFS='=' read -r k v; do [ -z \"$k\" ] && break; [ \"$k\" = databaseId ] && db=$v; [ \"$k\" = baseUrl ] && url=$v; done\necho \"apiKey=key-for-$db\"\necho \"apiSecret=secret-for-$url\"\n")
+	if err := os.Chmod(helper, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(home, ".onyx", "onyx-database.json"), `{"databaseId": "db2", "credentialHelper": "`+helper+`"}`)
+
+	rc, err := Resolve(Options{WorkDir: wd})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if rc.APIKey.Value != "" || rc.APISecret.Value != "" {
+		t.Fatalf("Resolve ran the credential helper: apiKey=%+v apiSecret=%+v", rc.APIKey, rc.APISecret)
+	}
+	if err := rc.FillCredentials(); err != nil {
+		t.Fatalf("FillCredentials: %v", err)
+	}
+	if rc.APIKey.Value != "key-for-db2" || rc.APISecret.Value != "secret-for-"+DefaultBaseURL || rc.APISecret.Source != "credentialHelper" {
+		t.Fatalf("apiKey=%+v apiSecret=%+v", rc.APIKey, rc.APISecret)
+	}
+}
+
+func TestResolve_FailingCredentialHelperOnlyFailsFillCredentials(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("helper script uses sh")
+	}
+	home, wd := isolate(t)
+	helper := filepath.Join(t.TempDir(), "helper.sh")
+	writeFile(t, helper, "#!/bin/sh\nexit 3\n")
+	if err := os.Chmod(helper, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(home, ".onyx", "onyx-database.json"), `{"databaseId": "db4", "credentialHelper": "`+helper+`"}`)
+
+	rc, err := Resolve(Options{WorkDir: wd})
+	if err != nil || rc.DatabaseID.Value != "db4" {
+		t.Fatalf("Resolve should not need the helper: databaseId=%+v err=%v", rc.DatabaseID, err)
+	}
+	if err := rc.FillCredentials(); err == nil {
+		t.Fatal("FillCredentials should report the helper failure")
+	}
+}
+
+func TestResolve_IgnoresCredentialHelperFromProjectConfig(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("helper script uses sh")
+	}
+	_, wd := isolate(t)
+	marker := filepath.Join(t.TempDir(), "ran")
+	helper := filepath.Join(t.TempDir(), "helper.sh")
+	writeFile(t, helper, "#!/bin/sh\ntouch "+marker+"\necho apiSecret=from-repo\n")
+	if err := os.Chmod(helper, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(wd, "onyx-database.json"), `{
+		"databaseId": "db3", "apiKey": "k", "credentialHelper": "`+helper+`",
+		"profiles": {"ci": {"credentialHelper": "`+helper+`"}}
+	}`)
+
+	for _, profile := range []string{"", "ci"} {
+		rc, err := Resolve(Options{WorkDir: wd, Profile: profile})
+		if err == nil {
+			err = rc.FillCredentials()
+		}
+		if err != nil {
+			t.Fatalf("Resolve(profile=%q): %v", profile, err)
+		}
+		if rc.CredentialHelper.Value != "" || rc.APISecret.Value != "" {
+			t.Fatalf("profile=%q: project helper was used: helper=%+v apiSecret=%+v", profile, rc.CredentialHelper, rc.APISecret)
+		}
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatal("credential helper from a project config file was executed")
+	}
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Credential is one stored set of API credentials, keyed by database ID.
+type Credential struct {
+	DatabaseID string `json:"databaseId"`
+	BaseURL    string `json:"baseUrl,omitempty"`
+	APIKey     string `json:"apiKey"`
+	APISecret  string `json:"apiSecret"`
+}
+
+// CredentialStore keeps credentials outside project directories, encrypted at rest with
+// AES-256-GCM. The key is generated on first use and kept in a separate file; both files are 0600
+// in a 0700 directory. Encryption protects the data file when it is copied on its own (backups,
+// dotfile sync); anyone who can read both files as the user can decrypt it, so use a
+// credentialHelper to keep secrets in an OS keychain instead.
+type CredentialStore struct {
+	Path    string // encrypted credentials file
+	KeyPath string // 32-byte key file
+}
+
+// DefaultCredentialStore returns the store under ~/.onyx managed by `onyx auth`.
+func DefaultCredentialStore() *CredentialStore {
+	dir := expandPath(filepath.Join("~", ".onyx"))
+	return &CredentialStore{
+		Path:    filepath.Join(dir, "credentials.enc"),
+		KeyPath: filepath.Join(dir, "credentials.key"),
+	}
+}
+
+// List returns all stored credentials sorted by database ID. A missing store is empty.
+func (s *CredentialStore) List() ([]Credential, error) {
+	entries, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Credential, 0, len(entries))
+	for _, c := range entries {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].DatabaseID < out[j].DatabaseID })
+	return out, nil
+}
+
+// Get returns the credential stored for databaseID.
+func (s *CredentialStore) Get(databaseID string) (Credential, bool, error) {
+	entries, err := s.load()
+	if err != nil {
+		return Credential{}, false, err
+	}
+	c, ok := entries[databaseID]
+	return c, ok, nil
+}
+
+// Put adds or replaces the credential for c.DatabaseID.
+func (s *CredentialStore) Put(c Credential) error {
+	if c.DatabaseID == "" {
+		return errors.New("credential requires a databaseId")
+	}
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	entries[c.DatabaseID] = c
+	return s.save(entries)
+}
+
+// Delete removes the credential for databaseID and reports whether one existed.
+func (s *CredentialStore) Delete(databaseID string) (bool, error) {
+	entries, err := s.load()
+	if err != nil {
+		return false, err
+	}
+	if _, ok := entries[databaseID]; !ok {
+		return false, nil
+	}
+	delete(entries, databaseID)
+	return true, s.save(entries)
+}
+
+func (s *CredentialStore) load() (map[string]Credential, error) {
+	entries := map[string]Credential{}
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	key, err := os.ReadFile(s.KeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("read credential key %s: %w", s.KeyPath, err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("credential store %s is corrupt", s.Path)
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt credential store %s: %w", s.Path, err)
+	}
+	if err := json.Unmarshal(plain, &entries); err != nil {
+		return nil, fmt.Errorf("parse credential store %s: %w", s.Path, err)
+	}
+	return entries, nil
+}
+
+func (s *CredentialStore) save(entries map[string]Credential) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o700); err != nil {
+		return err
+	}
+	key, err := os.ReadFile(s.KeyPath)
+	if errors.Is(err, os.ErrNotExist) {
+		key = make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return fmt.Errorf("generate credential key: %w", err)
+		}
+		if err := writePrivateFile(s.KeyPath, key); err != nil {
+			return fmt.Errorf("write credential key %s: %w", s.KeyPath, err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("read credential key %s: %w", s.KeyPath, err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	plain, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	return writePrivateFile(s.Path, gcm.Seal(nonce, nonce, plain, nil))
+}
+
+// writePrivateFile writes data readable by the user only. WriteFile keeps the mode of an existing
+// file, so it is tightened in case it was loosened.
+func writePrivateFile(path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0o600)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, errors.New("credential key must be 32 bytes")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
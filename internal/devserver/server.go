@@ -0,0 +1,376 @@
+// Package devserver is a local stand-in for the Onyx Schema API. It implements the endpoints
+// api.Client calls and keeps every revision on disk, so get/diff/validate/publish can run offline.
+package devserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OnyxDevTools/onyx-cli/internal/schema"
+)
+
+// Options configures a Server.
+type Options struct {
+	// Dir stores revisions as <Dir>/<databaseId>/<revisionId>.json. Records served by the query
+	// endpoint are read from <Dir>/<databaseId>/records/<table>.json (a JSON array), if present.
+	Dir string
+	// APIKey and APISecret, when set, must match the x-onyx-key/x-onyx-secret headers.
+	APIKey    string
+	APISecret string
+	// Now overrides the clock used for revision timestamps (tests).
+	Now func() time.Time
+}
+
+// Server serves /schemas/{db}, /schemas/{db}/validate, /schemas/history/{db},
+// /schemas/history/{db}/{revisionId} and the read-only /data/{db}/query/{table}.
+type Server struct {
+	opts Options
+	mu   sync.Mutex
+}
+
+// New returns a Server backed by opts.Dir.
+func New(opts Options) *Server {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Server{opts: opts}
+}
+
+// Seed publishes req as the first revision of databaseID when it has no revisions yet.
+func (s *Server) Seed(databaseID string, req schema.SchemaUpsertRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	revs, err := s.load(databaseID)
+	if err != nil || len(revs) > 0 {
+		return err
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	var doc revision
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	_, err = s.save(databaseID, doc, true)
+	return err
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.opts.APIKey != "" || s.opts.APISecret != "" {
+		if r.Header.Get("x-onyx-key") != s.opts.APIKey || r.Header.Get("x-onyx-secret") != s.opts.APISecret {
+			writeError(w, http.StatusUnauthorized, "invalid API key or secret", nil)
+			return
+		}
+	}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(parts) == 3 && parts[0] == "schemas" && parts[1] == "history" && r.Method == http.MethodGet:
+		s.handleHistory(w, parts[2])
+	case len(parts) == 4 && parts[0] == "schemas" && parts[1] == "history" && r.Method == http.MethodGet:
+		s.handleRevision(w, parts[2], parts[3])
+	case len(parts) == 3 && parts[0] == "schemas" && parts[2] == "validate" && r.Method == http.MethodPost:
+		s.handleValidate(w, r)
+	case len(parts) == 2 && parts[0] == "schemas" && r.Method == http.MethodGet:
+		s.handleGet(w, r, parts[1])
+	case len(parts) == 2 && parts[0] == "schemas" && r.Method == http.MethodPut:
+		s.handlePut(w, r, parts[1])
+	case len(parts) == 4 && parts[0] == "data" && parts[2] == "query" && r.Method == http.MethodPut:
+		s.handleQuery(w, r, parts[1], parts[3])
+	case len(parts) >= 2 && (parts[0] == "schemas" || parts[0] == "data"):
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s not supported on %s", r.Method, r.URL.Path), nil)
+	default:
+		writeError(w, http.StatusNotFound, "unknown endpoint "+r.URL.Path, nil)
+	}
+}
+
+// handleGet serves the latest published revision; saved drafts are only visible in the history.
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request, db string) {
+	s.mu.Lock()
+	revs, err := s.load(db)
+	s.mu.Unlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	rev := revision{}
+	for i := len(revs) - 1; i >= 0; i-- {
+		if revs[i].meta().PublishedAt != "" {
+			rev = revs[i].clone()
+			break
+		}
+	}
+	rev["databaseId"], _ = json.Marshal(db)
+	tables, err := rev.tables()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	if filter := r.URL.Query().Get("tables"); filter != "" {
+		want := map[string]bool{}
+		for _, t := range strings.Split(filter, ",") {
+			want[strings.TrimSpace(t)] = true
+		}
+		filtered := []json.RawMessage{}
+		for _, t := range tables {
+			var named struct {
+				Name string `json:"name"`
+			}
+			if json.Unmarshal(t, &named) == nil && want[named.Name] {
+				filtered = append(filtered, t)
+			}
+		}
+		tables = filtered
+	}
+	rev["tables"], _ = json.Marshal(tables)
+	writeJSON(w, http.StatusOK, rev)
+}
+
+func (s *Server) handleHistory(w http.ResponseWriter, db string) {
+	s.mu.Lock()
+	revs, err := s.load(db)
+	s.mu.Unlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	// newest first, like the hosted API
+	out := make([]revision, 0, len(revs))
+	for i := len(revs) - 1; i >= 0; i-- {
+		out = append(out, revs[i])
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleRevision(w http.ResponseWriter, db, revisionID string) {
+	s.mu.Lock()
+	revs, err := s.load(db)
+	s.mu.Unlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	for _, rev := range revs {
+		if rev.meta().RevisionID == revisionID {
+			writeJSON(w, http.StatusOK, rev)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("revision %s not found", revisionID), nil)
+}
+
+// handleQuery answers a SelectQuery with up to pageSize (or the body's limit) stored records.
+// Tables without a records file return an empty page.
+func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request, db, table string) {
+	var query struct {
+		Limit int `json:"limit"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&query); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "invalid query: "+err.Error(), nil)
+		return
+	}
+	limit := query.Limit
+	if v := r.URL.Query().Get("pageSize"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid pageSize "+v, nil)
+			return
+		}
+		limit = n
+	}
+	records := []json.RawMessage{}
+	data, err := os.ReadFile(filepath.Join(s.dbDir(db), "records", filepath.Base(filepath.Clean("/"+table))+".json"))
+	if err != nil && !os.IsNotExist(err) {
+		writeError(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &records); err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("parse %s records: %v", table, err), nil)
+			return
+		}
+	}
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"records": records})
+}
+
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	req, _, ok := decodeRequest(w, r)
+	if !ok {
+		return
+	}
+	errs := validate(req)
+	valid := len(errs) == 0
+	writeJSON(w, http.StatusOK, schema.SchemaValidationResult{Valid: &valid, Errors: errs})
+}
+
+func (s *Server) handlePut(w http.ResponseWriter, r *http.Request, db string) {
+	req, doc, ok := decodeRequest(w, r)
+	if !ok {
+		return
+	}
+	if errs := validate(req); len(errs) > 0 {
+		writeError(w, http.StatusBadRequest, "schema validation failed", errs)
+		return
+	}
+	s.mu.Lock()
+	rev, err := s.save(db, doc, r.URL.Query().Get("publish") == "true")
+	s.mu.Unlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	writeJSON(w, http.StatusOK, rev)
+}
+
+// validate reports lint errors (warnings are accepted) in the API's validation shape.
+func validate(req schema.SchemaUpsertRequest) []schema.ValidationError {
+	var out []schema.ValidationError
+	for _, issue := range schema.LintSchema(req, schema.LintOptions{}) {
+		if issue.Severity == schema.SeverityError {
+			out = append(out, schema.ValidationError{Message: issue.Message})
+		}
+	}
+	return out
+}
+
+// decodeRequest returns the request body decoded, for validation, and as the raw document that is
+// stored, with legacy "entities" renamed to "tables".
+func decodeRequest(w http.ResponseWriter, r *http.Request) (schema.SchemaUpsertRequest, revision, bool) {
+	var req schema.SchemaUpsertRequest
+	var doc revision
+	data, err := io.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(data, &req)
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid schema JSON: "+err.Error(), nil)
+		return req, nil, false
+	}
+	if len(req.Tables) == 0 && len(req.Entities) > 0 {
+		req.Tables = req.Entities
+		doc["tables"] = doc["entities"]
+	}
+	req.Entities = nil
+	delete(doc, "entities")
+	return req, doc, true
+}
+
+// revision is a stored schema revision. It is kept as raw JSON so keys the CLI does not model
+// round-trip unchanged, as they do on the hosted API.
+type revision map[string]json.RawMessage
+
+func (r revision) meta() schema.SchemaMeta {
+	var meta schema.SchemaMeta
+	_ = json.Unmarshal(r["meta"], &meta)
+	return meta
+}
+
+func (r revision) tables() ([]json.RawMessage, error) {
+	tables := []json.RawMessage{}
+	if raw, ok := r["tables"]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &tables); err != nil {
+			return nil, fmt.Errorf("parse tables: %w", err)
+		}
+	}
+	return tables, nil
+}
+
+func (r revision) clone() revision {
+	out := make(revision, len(r))
+	for k, v := range r {
+		out[k] = v
+	}
+	return out
+}
+
+// load returns the stored revisions of db, oldest first. Callers hold s.mu.
+func (s *Server) load(db string) ([]revision, error) {
+	entries, err := os.ReadDir(s.dbDir(db))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	revs := make([]revision, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(s.dbDir(db), name))
+		if err != nil {
+			return nil, err
+		}
+		var rev revision
+		if err := json.Unmarshal(data, &rev); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+		revs = append(revs, rev)
+	}
+	return revs, nil
+}
+
+// save writes doc as the next revision of db. Callers hold s.mu.
+func (s *Server) save(db string, doc revision, publish bool) (revision, error) {
+	revs, err := s.load(db)
+	if err != nil {
+		return nil, err
+	}
+	now := s.opts.Now().UTC().Format(time.RFC3339)
+	meta := schema.SchemaMeta{RevisionID: fmt.Sprintf("rev-%06d", len(revs)+1), CreatedAt: now}
+	if publish {
+		meta.PublishedAt = now
+	}
+	rev := doc.clone()
+	tables, err := rev.tables()
+	if err != nil {
+		return nil, err
+	}
+	rev["tables"], _ = json.Marshal(tables)
+	rev["databaseId"], _ = json.Marshal(db)
+	rev["meta"], _ = json.Marshal(meta)
+	data, err := json.MarshalIndent(rev, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.dbDir(db), 0o755); err != nil {
+		return nil, err
+	}
+	return rev, os.WriteFile(filepath.Join(s.dbDir(db), meta.RevisionID+".json"), append(data, '\n'), 0o644)
+}
+
+func (s *Server) dbDir(db string) string {
+	return filepath.Join(s.opts.Dir, filepath.Base(filepath.Clean("/"+db)))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string, errs []schema.ValidationError) {
+	writeJSON(w, status, struct {
+		Message string                   `json:"message"`
+		Errors  []schema.ValidationError `json:"errors,omitempty"`
+	}{msg, errs})
+}
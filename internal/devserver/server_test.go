@@ -0,0 +1,144 @@
+package devserver
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OnyxDevTools/onyx-cli/internal/api"
+	"github.com/OnyxDevTools/onyx-cli/internal/schema"
+)
+
+func TestServer_PublishGetHistoryRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	srv := httptest.NewServer(New(Options{Dir: dir, APIKey: "k", APISecret: "s"}))
+	defer srv.Close()
+	c := api.NewClient(srv.URL, "db1", "k", "s")
+
+	rev, err := c.GetSchema(nil)
+	if err != nil || len(rev.Tables) != 0 {
+		t.Fatalf("empty database: rev=%#v err=%v", rev, err)
+	}
+
+	user := schema.SchemaTable{
+		Name:       "User",
+		Identifier: &schema.SchemaIdentifier{Name: "id", Type: "String"},
+		Attributes: []schema.SchemaAttribute{{Name: "id", Type: "String"}, {Name: "email", Type: "String"}},
+	}
+	published, err := c.UpdateSchema(schema.SchemaUpsertRequest{Tables: []schema.SchemaTable{user}}, true)
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if published.Meta == nil || published.Meta.RevisionID != "rev-000001" || published.Meta.PublishedAt == "" {
+		t.Fatalf("unexpected publish meta: %#v", published.Meta)
+	}
+	if _, err := c.UpdateSchema(schema.SchemaUpsertRequest{Tables: []schema.SchemaTable{user, {Name: "Team"}}}, true); err != nil {
+		t.Fatalf("second publish: %v", err)
+	}
+
+	// A fresh server over the same directory sees the stored revisions.
+	srv2 := httptest.NewServer(New(Options{Dir: dir}))
+	defer srv2.Close()
+	c2 := api.NewClient(srv2.URL, "db1", "", "")
+	latest, err := c2.GetSchema([]string{"Team"})
+	if err != nil || len(latest.Tables) != 1 || latest.Tables[0].Name != "Team" {
+		t.Fatalf("filtered get: rev=%#v err=%v", latest, err)
+	}
+	revs, err := c2.ListSchemaRevisions()
+	if err != nil || len(revs) != 2 || revs[0].Meta.RevisionID != "rev-000002" {
+		t.Fatalf("history: %#v err=%v", revs, err)
+	}
+	first, err := c2.GetSchemaRevision("rev-000001")
+	if err != nil || len(first.Tables) != 1 || first.Meta.RevisionID != "rev-000001" {
+		t.Fatalf("revision lookup: %#v err=%v", first, err)
+	}
+	if _, err := c2.GetSchemaRevision("rev-999999"); !errors.Is(err, api.ErrNotFound) || !strings.Contains(err.Error(), "onyx schema history") {
+		t.Fatalf("unknown revision: err=%v, want not found with a history hint", err)
+	}
+}
+
+func TestServer_ValidationAndAuth(t *testing.T) {
+	srv := httptest.NewServer(New(Options{Dir: t.TempDir(), APIKey: "k", APISecret: "s", Now: func() time.Time { return time.Unix(0, 0) }}))
+	defer srv.Close()
+	c := api.NewClient(srv.URL, "db1", "k", "s")
+
+	bad := schema.SchemaUpsertRequest{Tables: []schema.SchemaTable{{Name: "User"}, {Name: "User"}}}
+	res, err := c.ValidateSchema(bad)
+	if err != nil || res.Valid == nil || *res.Valid || len(res.Errors) == 0 {
+		t.Fatalf("validate: res=%#v err=%v", res, err)
+	}
+	if _, err := c.UpdateSchema(bad, true); !errors.Is(err, api.ErrValidation) {
+		t.Fatalf("publish invalid schema: err=%v, want validation error", err)
+	}
+
+	wrong := api.NewClient(srv.URL, "db1", "k", "nope", api.WithRetryPolicy(api.RetryPolicy{}))
+	if _, err := wrong.GetSchema(nil); !errors.Is(err, api.ErrUnauthorized) {
+		t.Fatalf("bad secret: err=%v, want unauthorized", err)
+	}
+}
+
+func TestServer_GetServesLatestPublishedRawRevision(t *testing.T) {
+	srv := httptest.NewServer(New(Options{Dir: t.TempDir()}))
+	defer srv.Close()
+	c := api.NewClient(srv.URL, "db1", "", "")
+
+	published := `{"tables":[{"name":"User","attributes":[{"name":"id","type":"String","description":"kept"}]}],"owner":"platform"}`
+	if _, err := c.UpdateSchemaRaw([]byte(published), true); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if _, err := c.UpdateSchemaRaw([]byte(`{"tables":[{"name":"Draft"}]}`), false); err != nil {
+		t.Fatalf("save draft: %v", err)
+	}
+
+	raw, rev, err := c.GetSchemaRawContext(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(rev.Tables) != 1 || rev.Tables[0].Name != "User" || rev.Meta.RevisionID != "rev-000001" {
+		t.Fatalf("get should serve the published revision, got %#v", rev)
+	}
+	for _, want := range []string{`"description":"kept"`, `"owner":"platform"`} {
+		if !strings.Contains(string(raw), want) {
+			t.Fatalf("unknown key %s dropped:\n%s", want, raw)
+		}
+	}
+	revs, err := c.ListSchemaRevisions()
+	if err != nil || len(revs) != 2 || revs[0].Meta.PublishedAt != "" {
+		t.Fatalf("history should list the draft first: %#v err=%v", revs, err)
+	}
+}
+
+func TestServer_QueryServesStoredRecords(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "db1", "records"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	stored := `[{"id":"1","address":{"city":"Oslo"}},{"id":"2"},{"id":"3"}]`
+	if err := os.WriteFile(filepath.Join(dir, "db1", "records", "User.json"), []byte(stored), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(New(Options{Dir: dir}))
+	defer srv.Close()
+	c := api.NewClient(srv.URL, "db1", "", "")
+
+	records, err := c.QueryRecordsContext(context.Background(), "User", 2)
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(records) != 2 || records[0]["id"] != "1" {
+		t.Fatalf("records = %#v, want the first 2 stored records", records)
+	}
+	empty, err := c.QueryRecordsContext(context.Background(), "Team", 2)
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("table without records: %#v err=%v", empty, err)
+	}
+	// Stored revisions are unaffected by the records directory.
+	if revs, err := c.ListSchemaRevisions(); err != nil || len(revs) != 0 {
+		t.Fatalf("history: %#v err=%v", revs, err)
+	}
+}
@@ -0,0 +1,243 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// canonicalAttributeTypes maps each known attribute type (see AttributeTypes) to its canonical
+// spelling; unknown types are left untouched.
+var canonicalAttributeTypes = map[string]string{
+	"string": "String", "text": "Text", "uuid": "UUID",
+	"bool": "Bool", "boolean": "Boolean",
+	"int": "Int", "integer": "Integer", "long": "Long", "short": "Short", "byte": "Byte",
+	"float": "Float", "double": "Double", "decimal": "Decimal", "number": "Number",
+	"date": "Date", "datetime": "DateTime", "timestamp": "Timestamp", "timestamptz": "TimestampTz",
+	"json": "JSON", "object": "Object", "record": "Record", "map": "Map", "embeddedobject": "EmbeddedObject",
+}
+
+// canonicalKeyOrder ranks known object keys; other keys follow in alphabetical order.
+var canonicalKeyOrder = []string{
+	"databaseId", "name", "identifier", "partition", "generator", "type", "isNullable", "shape", "minimumScore",
+	"event", "resolver", "returns", "trigger", "attributes", "indexes", "resolvers", "triggers",
+	"include", "tables", "meta", "revisionId", "createdAt", "publishedAt",
+}
+
+// tableSections are the per-table lists sorted by name.
+var tableSections = []string{"attributes", "indexes", "resolvers", "triggers"}
+
+// CanonicalizeSchema rewrites schema JSON into a stable form so repeated pulls and hand edits
+// produce minimal diffs: tables and their attributes, indexes, resolvers and triggers are sorted
+// by name (the identifier attribute first), legacy "entities" is folded into "tables",
+// attribute/identifier types, generators, index types and trigger events get canonical casing,
+// and object keys follow a fixed order.
+// Unknown fields are kept. The input may also be a single table or an array of tables, as found
+// in split schema files. The result is compact; use EncodeSchemaFile to indent it.
+func CanonicalizeSchema(doc []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("decode schema json: %w", err)
+	}
+	switch t := v.(type) {
+	case []any:
+		canonicalTables(t)
+	case map[string]any:
+		if _, isTable := t["name"]; isTable && t["tables"] == nil && t["entities"] == nil {
+			canonicalTable(t)
+			break
+		}
+		if tables, ok := t["tables"].([]any); !ok || len(tables) == 0 {
+			if entities, ok := t["entities"]; ok {
+				t["tables"] = entities
+			}
+		}
+		delete(t, "entities")
+		if tables, ok := t["tables"].([]any); ok {
+			canonicalTables(tables)
+		}
+	default:
+		return nil, fmt.Errorf("decode schema json: expected an object or an array of tables")
+	}
+	var buf bytes.Buffer
+	if err := writeCanonicalJSON(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// IsCanonicalSchema reports whether schema JSON already satisfies CanonicalizeSchema apart from
+// object key order, which decoders such as TOML's do not keep.
+func IsCanonicalSchema(doc []byte) (bool, error) {
+	canonical, err := CanonicalizeSchema(doc)
+	if err != nil {
+		return false, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return false, fmt.Errorf("decode schema json: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := writeCanonicalJSON(&buf, v); err != nil {
+		return false, err
+	}
+	return bytes.Equal(buf.Bytes(), canonical), nil
+}
+
+func canonicalTables(tables []any) {
+	for _, t := range tables {
+		if m, ok := t.(map[string]any); ok {
+			canonicalTable(m)
+		}
+	}
+	sortByName(tables, "")
+}
+
+func canonicalTable(t map[string]any) {
+	var idName string
+	if id, ok := t["identifier"].(map[string]any); ok {
+		canonicalString(id, "type", func(s string) string { return canonicalAttributeTypes[strings.ToLower(s)] })
+		canonicalString(id, "generator", func(s string) string { return matchFold(IdentifierGenerators, s) })
+		idName, _ = id["name"].(string)
+	}
+	for _, section := range tableSections {
+		items, ok := t[section].([]any)
+		if !ok {
+			continue
+		}
+		for _, item := range items {
+			m, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			switch section {
+			case "attributes":
+				canonicalString(m, "type", func(s string) string { return canonicalAttributeTypes[strings.ToLower(s)] })
+			case "indexes":
+				canonicalString(m, "type", strings.ToUpper)
+			case "triggers":
+				canonicalString(m, "event", func(s string) string { return matchFold(TriggerEvents, s) })
+			}
+		}
+		first := ""
+		if section == "attributes" {
+			first = idName
+		}
+		sortByName(items, first)
+	}
+}
+
+// canonicalString replaces m[key] with canon(value) when the value is a string and canon knows it.
+func canonicalString(m map[string]any, key string, canon func(string) string) {
+	s, ok := m[key].(string)
+	if !ok {
+		return
+	}
+	if c := canon(strings.TrimSpace(s)); c != "" {
+		m[key] = c
+	}
+}
+
+func matchFold(values []string, s string) string {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return v
+		}
+	}
+	return ""
+}
+
+// sortByName orders objects by their "name" (see nameLess); the object named first, if any, leads.
+func sortByName(items []any, first string) {
+	name := func(v any) string {
+		if m, ok := v.(map[string]any); ok {
+			s, _ := m["name"].(string)
+			return s
+		}
+		return ""
+	}
+	sort.SliceStable(items, func(i, j int) bool { return nameLess(name(items[i]), name(items[j]), first) })
+}
+
+// nameLess orders names case-insensitively (case-sensitive on ties), with first ahead of the rest.
+func nameLess(a, b, first string) bool {
+	if first != "" && (a == first) != (b == first) {
+		return a == first
+	}
+	if la, lb := strings.ToLower(a), strings.ToLower(b); la != lb {
+		return la < lb
+	}
+	return a < b
+}
+
+// keyRank orders object keys: known keys by canonicalKeyOrder, then the rest.
+func keyRank(k string) int {
+	for i, known := range canonicalKeyOrder {
+		if k == known {
+			return i
+		}
+	}
+	return len(canonicalKeyOrder)
+}
+
+func writeCanonicalJSON(buf *bytes.Buffer, v any) error {
+	switch t := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if ri, rj := keyRank(keys[i]), keyRank(keys[j]); ri != rj {
+				return ri < rj
+			}
+			return keys[i] < keys[j]
+		})
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONValue(buf, k); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeCanonicalJSON(buf, t[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range t {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonicalJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		return writeJSONValue(buf, t)
+	}
+	return nil
+}
+
+// writeJSONValue encodes a scalar without HTML escaping so resolver and trigger code stays readable.
+func writeJSONValue(buf *bytes.Buffer, v any) error {
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("encode schema json: %w", err)
+	}
+	buf.Write(bytes.TrimRight(out.Bytes(), "\n"))
+	return nil
+}
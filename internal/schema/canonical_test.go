@@ -0,0 +1,130 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCanonicalizeSchema_SortsAndNormalizes(t *testing.T) {
+	in := `{
+  "meta": {"publishedAt": "p", "revisionId": "r"},
+  "entities": [
+    {"triggers": [{"trigger": "a && b", "event": "prepersist", "name": "z"}, {"name": "a", "event": "PostDelete", "trigger": "x"}],
+     "name": "user",
+     "attributes": [{"type": "string", "name": "email"}, {"name": "Age", "type": "INT", "custom": 1.50}, {"name": "blob", "type": "Widget"}],
+     "identifier": {"type": "string", "name": "email", "generator": "uuid"},
+     "indexes": [{"name": "email", "type": "lucene", "minimumScore": 0.5}]},
+    {"name": "Account", "attributes": []}
+  ],
+  "databaseId": "db1"
+}`
+	want := `{"databaseId":"db1","tables":[` +
+		`{"name":"Account","attributes":[]},` +
+		`{"name":"user","identifier":{"name":"email","generator":"UUID","type":"String"},` +
+		`"attributes":[{"name":"email","type":"String"},{"name":"Age","type":"Int","custom":1.50},{"name":"blob","type":"Widget"}],` +
+		`"indexes":[{"name":"email","type":"LUCENE","minimumScore":0.5}],` +
+		`"triggers":[{"name":"a","event":"PostDelete","trigger":"x"},{"name":"z","event":"PrePersist","trigger":"a && b"}]}` +
+		`],"meta":{"revisionId":"r","publishedAt":"p"}}`
+
+	got, err := CanonicalizeSchema([]byte(in))
+	if err != nil {
+		t.Fatalf("CanonicalizeSchema: %v", err)
+	}
+	if string(got) != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+	again, err := CanonicalizeSchema(got)
+	if err != nil || string(again) != string(got) {
+		t.Fatalf("canonical form is not stable: %s (err %v)", again, err)
+	}
+}
+
+func TestCanonicalizeSchema_PrefersTablesOverEntities(t *testing.T) {
+	got, err := CanonicalizeSchema([]byte(`{"tables":[{"name":"B"},{"name":"A"}],"entities":[{"name":"B"},{"name":"A"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `{"tables":[{"name":"A"},{"name":"B"}]}` {
+		t.Fatalf("got %s", got)
+	}
+}
+
+func TestCanonicalizeSchema_SplitTableFiles(t *testing.T) {
+	table, err := CanonicalizeSchema([]byte(`{"attributes":[{"name":"b","type":"long"},{"name":"a","type":"text"}],"name":"T"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(table) != `{"name":"T","attributes":[{"name":"a","type":"Text"},{"name":"b","type":"Long"}]}` {
+		t.Fatalf("table file: %s", table)
+	}
+	list, err := CanonicalizeSchema([]byte(`[{"name":"b"},{"name":"A"}]`))
+	if err != nil || !strings.HasPrefix(string(list), `[{"name":"A"}`) {
+		t.Fatalf("table list: %s (err %v)", list, err)
+	}
+	if _, err := CanonicalizeSchema([]byte(`"nope"`)); err == nil {
+		t.Fatal("expected an error for a scalar document")
+	}
+}
+
+func TestCanonicalizeSchemaYAML_KeepsComments(t *testing.T) {
+	in := `# Billing schema
+entities:
+  # users sign in by email
+  - name: user
+    attributes:
+      - name: email # login
+        type: string
+      # internal key
+      - type: long
+        name: id
+    identifier: {name: id, type: long}
+  - name: Account
+`
+	got, err := CanonicalizeSchemaYAML([]byte(in))
+	if err != nil {
+		t.Fatalf("CanonicalizeSchemaYAML: %v", err)
+	}
+	for _, comment := range []string{"# Billing schema", "# users sign in by email", "# login", "# internal key"} {
+		if !strings.Contains(string(got), comment) {
+			t.Errorf("comment %q lost:\n%s", comment, got)
+		}
+	}
+	order := []string{"tables:", "name: Account", "name: user", "identifier:", "# internal key", "name: id", "type: Long", "name: email # login", "type: String"}
+	rest := string(got)
+	for _, s := range order {
+		i := strings.Index(rest, s)
+		if i < 0 {
+			t.Fatalf("%q missing or out of order:\n%s", s, got)
+		}
+		rest = rest[i+len(s):]
+	}
+	again, err := CanonicalizeSchemaYAML(got)
+	if err != nil || string(again) != string(got) {
+		t.Fatalf("canonical form is not stable:\n%s\n(err %v)", again, err)
+	}
+
+	// schema get writes YAML through EncodeSchemaFile; fmt must leave it alone
+	canonical, err := CanonicalizeSchema([]byte(`{"tables":[{"name":"T","attributes":[{"name":"a","type":"Text"}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pulled, err := EncodeSchemaFile(canonical, FileFormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if formatted, err := CanonicalizeSchemaYAML(pulled); err != nil || string(formatted) != string(pulled) {
+		t.Fatalf("pulled YAML changed:\n%s\nto\n%s\n(err %v)", pulled, formatted, err)
+	}
+}
+
+func TestIsCanonicalSchema_IgnoresKeyOrder(t *testing.T) {
+	for doc, want := range map[string]bool{
+		`{"tables":[{"attributes":[{"type":"Int","name":"a"}],"name":"A"}]}`: true,
+		`{"tables":[{"name":"B"},{"name":"A"}]}`:                             false,
+		`{"tables":[{"name":"A","attributes":[{"name":"a","type":"int"}]}]}`: false,
+	} {
+		if got, err := IsCanonicalSchema([]byte(doc)); err != nil || got != want {
+			t.Errorf("IsCanonicalSchema(%s) = %v, %v; want %v", doc, got, err, want)
+		}
+	}
+}
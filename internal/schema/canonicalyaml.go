@@ -0,0 +1,187 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CanonicalizeSchemaYAML applies the rules of CanonicalizeSchema to a YAML schema file by
+// reordering its node tree in place, so comments and scalar styles survive. Comments on a
+// dropped legacy "entities" key are lost.
+func CanonicalizeSchemaYAML(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decode schema yaml: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, fmt.Errorf("decode schema yaml: empty document")
+	}
+	root := doc.Content[0]
+	switch root.Kind {
+	case yaml.SequenceNode:
+		canonicalTableNodes(root)
+	case yaml.MappingNode:
+		if yamlValue(root, "name") != nil && yamlValue(root, "tables") == nil && yamlValue(root, "entities") == nil {
+			canonicalTableNode(root)
+			break
+		}
+		if entities := yamlValue(root, "entities"); entities != nil {
+			if tables := yamlValue(root, "tables"); tables == nil {
+				// keep the key node, and its comments, under the new name
+				yamlKey(root, "entities").Value = "tables"
+			} else {
+				if tables.Kind != yaml.SequenceNode || len(tables.Content) == 0 {
+					*tables = *entities
+				}
+				deleteYAMLKey(root, "entities")
+			}
+		}
+		if tables := yamlValue(root, "tables"); tables != nil && tables.Kind == yaml.SequenceNode {
+			canonicalTableNodes(tables)
+		}
+	}
+	sortYAMLKeys(root)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("encode schema yaml: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encode schema yaml: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func canonicalTableNodes(tables *yaml.Node) {
+	for _, t := range tables.Content {
+		if t.Kind == yaml.MappingNode {
+			canonicalTableNode(t)
+		}
+	}
+	sortYAMLByName(tables, "")
+}
+
+func canonicalTableNode(t *yaml.Node) {
+	var idName string
+	if id := yamlValue(t, "identifier"); id != nil && id.Kind == yaml.MappingNode {
+		canonicalYAMLString(id, "type", func(s string) string { return canonicalAttributeTypes[strings.ToLower(s)] })
+		canonicalYAMLString(id, "generator", func(s string) string { return matchFold(IdentifierGenerators, s) })
+		if name := yamlValue(id, "name"); isYAMLString(name) {
+			idName = name.Value
+		}
+	}
+	for _, section := range tableSections {
+		items := yamlValue(t, section)
+		if items == nil || items.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, m := range items.Content {
+			if m.Kind != yaml.MappingNode {
+				continue
+			}
+			switch section {
+			case "attributes":
+				canonicalYAMLString(m, "type", func(s string) string { return canonicalAttributeTypes[strings.ToLower(s)] })
+			case "indexes":
+				canonicalYAMLString(m, "type", strings.ToUpper)
+			case "triggers":
+				canonicalYAMLString(m, "event", func(s string) string { return matchFold(TriggerEvents, s) })
+			}
+		}
+		first := ""
+		if section == "attributes" {
+			first = idName
+		}
+		sortYAMLByName(items, first)
+	}
+}
+
+// canonicalYAMLString is canonicalString for a YAML mapping.
+func canonicalYAMLString(m *yaml.Node, key string, canon func(string) string) {
+	v := yamlValue(m, key)
+	if !isYAMLString(v) {
+		return
+	}
+	if c := canon(strings.TrimSpace(v.Value)); c != "" {
+		v.Value = c
+	}
+}
+
+// sortYAMLByName is sortByName for a YAML sequence of mappings.
+func sortYAMLByName(seq *yaml.Node, first string) {
+	name := func(n *yaml.Node) string {
+		if v := yamlValue(n, "name"); isYAMLString(v) {
+			return v.Value
+		}
+		return ""
+	}
+	sort.SliceStable(seq.Content, func(i, j int) bool { return nameLess(name(seq.Content[i]), name(seq.Content[j]), first) })
+}
+
+// sortYAMLKeys orders the keys of every mapping under n as writeCanonicalJSON does. Comments
+// are attached to the key and value nodes, so they move with them.
+func sortYAMLKeys(n *yaml.Node) {
+	if n.Kind == yaml.MappingNode {
+		pairs := make([][2]*yaml.Node, 0, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			pairs = append(pairs, [2]*yaml.Node{n.Content[i], n.Content[i+1]})
+		}
+		sort.SliceStable(pairs, func(i, j int) bool {
+			ki, kj := pairs[i][0].Value, pairs[j][0].Value
+			if ri, rj := keyRank(ki), keyRank(kj); ri != rj {
+				return ri < rj
+			}
+			return ki < kj
+		})
+		n.Content = n.Content[:0]
+		for _, p := range pairs {
+			n.Content = append(n.Content, p[0], p[1])
+		}
+	}
+	for _, c := range n.Content {
+		sortYAMLKeys(c)
+	}
+}
+
+func yamlKey(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i]
+		}
+	}
+	return nil
+}
+
+func yamlValue(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func deleteYAMLKey(m *yaml.Node, key string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+func isYAMLString(n *yaml.Node) bool {
+	return n != nil && n.Kind == yaml.ScalarNode && n.ShortTag() == "!!str"
+}
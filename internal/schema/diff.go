@@ -86,6 +86,7 @@ func diffEntity(api SchemaEntity, local SchemaEntity) *SchemaTableDiff {
 	if !hasChange {
 		return nil
 	}
+	classifyTableDiff(&diff)
 	return &diff
 }
 
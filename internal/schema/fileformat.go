@@ -0,0 +1,189 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Schema file formats. JSON is what the API speaks; YAML and TOML are converted on read/write.
+const (
+	FileFormatJSON = "json"
+	FileFormatYAML = "yaml"
+	FileFormatTOML = "toml"
+)
+
+// FileFormats lists the accepted --format values for schema files.
+var FileFormats = []string{FileFormatJSON, FileFormatYAML, FileFormatTOML}
+
+// FileFormatForPath picks the format from the file extension (.yaml/.yml, .toml); anything else is JSON.
+func FileFormatForPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FileFormatYAML
+	case ".toml":
+		return FileFormatTOML
+	default:
+		return FileFormatJSON
+	}
+}
+
+// ParseFileFormat validates a --format value ("yml" is accepted for yaml).
+func ParseFileFormat(s string) (string, error) {
+	switch f := strings.ToLower(strings.TrimSpace(s)); f {
+	case FileFormatJSON, FileFormatYAML, FileFormatTOML:
+		return f, nil
+	case "yml":
+		return FileFormatYAML, nil
+	default:
+		return "", fmt.Errorf("invalid format %q (expected %s)", s, strings.Join(FileFormats, ", "))
+	}
+}
+
+// DecodeSchemaFile converts schema file contents in the given format to JSON.
+// JSON input is returned unchanged so unknown fields and formatting survive.
+func DecodeSchemaFile(data []byte, format string) ([]byte, error) {
+	var v any
+	switch format {
+	case FileFormatYAML:
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("decode schema yaml: %w", err)
+		}
+	case FileFormatTOML:
+		var m map[string]any
+		if err := toml.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("decode schema toml: %w", err)
+		}
+		v = m
+	default:
+		return data, nil
+	}
+	if v == nil {
+		return []byte{}, nil
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encode schema json: %w", err)
+	}
+	return out, nil
+}
+
+// EncodeSchemaFile renders schema JSON in the given format. JSON is indented; YAML keeps the
+// JSON key order; TOML drops nulls (it has no null) and orders keys as the encoder does.
+func EncodeSchemaFile(data []byte, format string) ([]byte, error) {
+	switch format {
+	case FileFormatYAML:
+		node, err := jsonToYAMLNode(json.NewDecoder(bytes.NewReader(data)))
+		if err != nil {
+			return nil, fmt.Errorf("decode schema json: %w", err)
+		}
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(node); err != nil {
+			return nil, fmt.Errorf("encode schema yaml: %w", err)
+		}
+		return buf.Bytes(), enc.Close()
+	case FileFormatTOML:
+		var m map[string]any
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("decode schema json: %w", err)
+		}
+		dropNulls(m)
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(m); err != nil {
+			return nil, fmt.Errorf("encode schema toml: %w", err)
+		}
+		return buf.Bytes(), nil
+	default:
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, data, "", "  "); err != nil {
+			return nil, fmt.Errorf("encode schema json: %w", err)
+		}
+		buf.WriteByte('\n')
+		return buf.Bytes(), nil
+	}
+}
+
+// jsonToYAMLNode streams JSON tokens into a yaml.Node so object key order is preserved.
+func jsonToYAMLNode(dec *json.Decoder) (*yaml.Node, error) {
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	return tokenToYAMLNode(dec, tok)
+}
+
+func tokenToYAMLNode(dec *json.Decoder, tok json.Token) (*yaml.Node, error) {
+	switch t := tok.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		end := json.Delim(']')
+		if t == '{' {
+			node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			end = '}'
+		}
+		for {
+			next, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			if next == end {
+				return node, nil
+			}
+			if node.Kind == yaml.MappingNode {
+				key, ok := next.(string)
+				if !ok {
+					return nil, fmt.Errorf("unexpected object key %v", next)
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key})
+				if next, err = dec.Token(); err != nil {
+					return nil, err
+				}
+			}
+			child, err := tokenToYAMLNode(dec, next)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t}, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(t.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: t.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(t)}, nil
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	default:
+		return nil, io.ErrUnexpectedEOF
+	}
+}
+
+func dropNulls(v any) {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			if val == nil {
+				delete(t, k)
+				continue
+			}
+			dropNulls(val)
+		}
+	case []any:
+		for _, val := range t {
+			dropNulls(val)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const fileFormatFixture = `{"tables":[{"name":"User","identifier":{"name":"id","type":"String"},"attributes":[{"name":"id","type":"String"},{"name":"bio","type":"String","isNullable":true}],"indexes":[{"name":"bio","type":"LUCENE","minimumScore":0.5}]}]}`
+
+func TestEncodeDecodeSchemaFile_RoundTrips(t *testing.T) {
+	var want any
+	if err := json.Unmarshal([]byte(fileFormatFixture), &want); err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{FileFormatYAML, FileFormatTOML} {
+		encoded, err := EncodeSchemaFile([]byte(fileFormatFixture), format)
+		if err != nil {
+			t.Fatalf("%s encode: %v", format, err)
+		}
+		decoded, err := DecodeSchemaFile(encoded, format)
+		if err != nil {
+			t.Fatalf("%s decode: %v\n%s", format, err, encoded)
+		}
+		var got any
+		if err := json.Unmarshal(decoded, &got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s round trip changed the schema:\n%s", format, decoded)
+		}
+	}
+}
+
+func TestEncodeSchemaFile_YAMLKeepsKeyOrder(t *testing.T) {
+	out, err := EncodeSchemaFile([]byte(fileFormatFixture), FileFormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(out)
+	if strings.Index(text, "name: User") > strings.Index(text, "identifier:") {
+		t.Fatalf("expected table name before identifier:\n%s", text)
+	}
+}
+
+func TestFileFormatForPath(t *testing.T) {
+	for path, want := range map[string]string{
+		"onyx.schema.json":          FileFormatJSON,
+		"api/onyx.schema.YML":       FileFormatYAML,
+		"onyx.schema.yaml":          FileFormatYAML,
+		"onyx.schema.toml":          FileFormatTOML,
+		"git:main:onyx.schema.toml": FileFormatTOML,
+	} {
+		if got := FileFormatForPath(path); got != want {
+			t.Fatalf("FileFormatForPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -2,25 +2,49 @@ package schema
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
+// DiffLabels names the two schemas being compared. File, when set, is the local schema file
+// that annotations should point at.
+type DiffLabels struct {
+	Left  string // baseline schema (defaults to "API schema")
+	Right string // proposed schema (defaults to "local schema")
+	File  string
+}
+
+func (l DiffLabels) left() string {
+	if l.Left == "" {
+		return "API schema"
+	}
+	return l.Left
+}
+
 // FormatSchemaDiff returns a YAML-style string similar to the TypeScript CLI.
 func FormatSchemaDiff(diff SchemaDiff, filePath string) string {
+	return FormatSchemaDiffBetween(diff, DiffLabels{Right: filePath, File: filePath})
+}
+
+// FormatSchemaDiffBetween is FormatSchemaDiff with explicit labels for both sides.
+func FormatSchemaDiffBetween(diff SchemaDiff, labels DiffLabels) string {
 	hasChanges := len(diff.NewTables) > 0 || len(diff.RemovedTables) > 0 || len(diff.ChangedTables) > 0
 	if !hasChanges {
-		if filePath == "" {
-			filePath = "local schema"
+		right := labels.Right
+		if right == "" {
+			right = "local schema"
 		}
-		return "No differences found between API schema and " + filePath + ".\n"
+		return "No differences found between " + labels.left() + " and " + right + ".\n"
 	}
 
 	buf := &bytes.Buffer{}
 	header := "# Schema diff"
-	if filePath != "" {
-		header = "# Diff between API schema and " + filePath
+	if labels.Right != "" {
+		header = "# Diff between " + labels.left() + " and " + labels.Right
 	}
 	buf.WriteString(header)
 	buf.WriteString("\n\n")
@@ -57,6 +81,7 @@ func FormatSchemaDiff(diff SchemaDiff, filePath string) string {
 		if t.Triggers != nil && (len(t.Triggers.Added) > 0 || len(t.Triggers.Removed) > 0 || len(t.Triggers.Changed) > 0) {
 			prunedTable.Triggers = t.Triggers
 		}
+		prunedTable.Changes = t.Changes
 		if prunedTable.Partition != nil || prunedTable.Identifier != nil || prunedTable.Attributes != nil || prunedTable.Indexes != nil || prunedTable.Resolvers != nil || prunedTable.Triggers != nil {
 			changed = append(changed, prunedTable)
 		}
@@ -76,3 +101,123 @@ func FormatSchemaDiff(diff SchemaDiff, filePath string) string {
 	enc.Close()
 	return buf.String()
 }
+
+// Diff output formats accepted by `onyx schema diff --format`.
+const (
+	DiffFormatYAML     = "yaml"
+	DiffFormatJSON     = "json"
+	DiffFormatMarkdown = "markdown"
+	DiffFormatGitHub   = "github"
+)
+
+// FormatSchemaDiffAs renders the diff in the requested format (yaml, json, markdown, or github).
+func FormatSchemaDiffAs(diff SchemaDiff, labels DiffLabels, format string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", DiffFormatYAML, "yml":
+		return FormatSchemaDiffBetween(diff, labels), nil
+	case DiffFormatJSON:
+		return FormatSchemaDiffJSON(diff)
+	case DiffFormatMarkdown, "md":
+		return FormatSchemaDiffMarkdown(diff, labels), nil
+	case DiffFormatGitHub:
+		return FormatSchemaDiffGitHub(diff, labels.File), nil
+	default:
+		return "", fmt.Errorf("unknown diff format %q (expected yaml, json, markdown, or github)", format)
+	}
+}
+
+// FormatSchemaDiffJSON emits the SchemaDiff struct as indented JSON (empty lists instead of null).
+func FormatSchemaDiffJSON(diff SchemaDiff) (string, error) {
+	if diff.NewTables == nil {
+		diff.NewTables = []string{}
+	}
+	if diff.RemovedTables == nil {
+		diff.RemovedTables = []string{}
+	}
+	if diff.ChangedTables == nil {
+		diff.ChangedTables = []SchemaTableDiff{}
+	}
+	out, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encode diff: %w", err)
+	}
+	return string(out) + "\n", nil
+}
+
+// FormatSchemaDiffMarkdown renders a summary and a change table suitable for PR comments.
+func FormatSchemaDiffMarkdown(diff SchemaDiff, labels DiffLabels) string {
+	right := labels.Right
+	if right == "" {
+		right = "local schema"
+	}
+	changes := diff.AllChanges()
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "### Schema diff: `%s` vs `%s`\n\n", labels.left(), right)
+	if len(changes) == 0 {
+		buf.WriteString("No differences found.\n")
+		return buf.String()
+	}
+
+	counts := map[ChangeImpact]int{}
+	for _, c := range changes {
+		counts[c.Impact]++
+	}
+	fmt.Fprintf(buf, "**Impact: %s** — %d breaking, %d potentially-breaking, %d safe\n\n",
+		diff.Impact(), counts[ImpactBreaking], counts[ImpactPotentiallyBreaking], counts[ImpactSafe])
+
+	buf.WriteString("| Impact | Table | Target | Change | Detail |\n")
+	buf.WriteString("|--------|-------|--------|--------|--------|\n")
+	for _, c := range changes {
+		fmt.Fprintf(buf, "| %s | %s | %s | %s | %s |\n",
+			c.Impact, markdownCell(c.Table), markdownCell(c.Target), c.Kind, markdownCell(c.Detail))
+	}
+	return buf.String()
+}
+
+// FormatSchemaDiffGitHub emits GitHub Actions workflow commands: ::error for breaking changes,
+// ::warning for potentially-breaking changes, and ::notice for safe ones.
+func FormatSchemaDiffGitHub(diff SchemaDiff, filePath string) string {
+	buf := &bytes.Buffer{}
+	for _, c := range diff.AllChanges() {
+		level := "notice"
+		switch c.Impact {
+		case ImpactBreaking:
+			level = "error"
+		case ImpactPotentiallyBreaking:
+			level = "warning"
+		}
+		subject := c.Table
+		if c.Target != "" {
+			subject += "." + c.Target
+		}
+		msg := subject + ": " + c.Kind
+		if c.Detail != "" {
+			msg += " (" + c.Detail + ")"
+		}
+		props := "title=" + githubProperty("Schema "+string(c.Impact)+" change")
+		if filePath != "" {
+			props = "file=" + githubProperty(filePath) + "," + props
+		}
+		fmt.Fprintf(buf, "::%s %s::%s\n", level, props, githubData(msg))
+	}
+	return buf.String()
+}
+
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// githubData escapes workflow command message data.
+func githubData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+// githubProperty escapes workflow command property values.
+func githubProperty(s string) string {
+	s = githubData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
@@ -0,0 +1,66 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func sampleFormatDiff() SchemaDiff {
+	api := SchemaRevision{Tables: []SchemaTable{{
+		Name:       "User",
+		Attributes: []SchemaAttribute{{Name: "id", Type: "String"}, {Name: "email", Type: "String"}},
+	}}}
+	local := SchemaUpsertRequest{Tables: []SchemaTable{{
+		Name:       "User",
+		Attributes: []SchemaAttribute{{Name: "id", Type: "String"}, {Name: "bio", Type: "String", IsNullable: true}},
+	}}}
+	return ComputeSchemaDiff(api, local)
+}
+
+func TestFormatSchemaDiffAs_JSON(t *testing.T) {
+	out, err := FormatSchemaDiffAs(sampleFormatDiff(), DiffLabels{Right: "onyx.schema.json"}, "json")
+	if err != nil {
+		t.Fatalf("format json: %v", err)
+	}
+	var decoded SchemaDiff
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("json output does not round-trip: %v\n%s", err, out)
+	}
+	if len(decoded.ChangedTables) != 1 || decoded.NewTables == nil {
+		t.Fatalf("unexpected decoded diff: %#v", decoded)
+	}
+}
+
+func TestFormatSchemaDiffAs_MarkdownAndGitHub(t *testing.T) {
+	diff := sampleFormatDiff()
+
+	md, err := FormatSchemaDiffAs(diff, DiffLabels{Right: "onyx.schema.json"}, "markdown")
+	if err != nil {
+		t.Fatalf("format markdown: %v", err)
+	}
+	for _, want := range []string{"**Impact: breaking**", "| breaking | User | email | attribute-removed |", "| safe | User | bio | attribute-added |"} {
+		if !strings.Contains(md, want) {
+			t.Fatalf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	gh, err := FormatSchemaDiffAs(diff, DiffLabels{Right: "api/onyx.schema.json", File: "api/onyx.schema.json"}, "github")
+	if err != nil {
+		t.Fatalf("format github: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(gh), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 annotations, got %q", gh)
+	}
+	if !strings.HasPrefix(lines[0], "::notice file=api/onyx.schema.json,title=Schema safe change::User.bio") {
+		t.Fatalf("unexpected first annotation %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "::error file=api/onyx.schema.json,title=Schema breaking change::User.email") {
+		t.Fatalf("unexpected second annotation %q", lines[1])
+	}
+
+	if _, err := FormatSchemaDiffAs(diff, DiffLabels{}, "xml"); err == nil {
+		t.Fatalf("expected error for unknown format")
+	}
+}
@@ -0,0 +1,271 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChangeImpact classifies how risky a schema change is for existing data and clients.
+type ChangeImpact string
+
+const (
+	ImpactSafe                ChangeImpact = "safe"
+	ImpactPotentiallyBreaking ChangeImpact = "potentially-breaking"
+	ImpactBreaking            ChangeImpact = "breaking"
+)
+
+// Change kinds recorded on SchemaChange.
+const (
+	ChangeTableAdded        = "table-added"
+	ChangeTableRemoved      = "table-removed"
+	ChangePartitionChanged  = "partition-changed"
+	ChangeIdentifierChanged = "identifier-changed"
+	ChangeAttributeAdded    = "attribute-added"
+	ChangeAttributeRemoved  = "attribute-removed"
+	ChangeAttributeType     = "attribute-type-changed"
+	ChangeAttributeNullable = "attribute-nullability-changed"
+	ChangeIndexAdded        = "index-added"
+	ChangeIndexRemoved      = "index-removed"
+	ChangeIndexChanged      = "index-changed"
+	ChangeResolverAdded     = "resolver-added"
+	ChangeResolverRemoved   = "resolver-removed"
+	ChangeResolverChanged   = "resolver-changed"
+	ChangeTriggerAdded      = "trigger-added"
+	ChangeTriggerRemoved    = "trigger-removed"
+	ChangeTriggerChanged    = "trigger-changed"
+)
+
+// SchemaChange is a single classified change within a table (or the table itself).
+type SchemaChange struct {
+	Table  string       `json:"table"`
+	Kind   string       `json:"kind"`
+	Target string       `json:"target,omitempty"`
+	Impact ChangeImpact `json:"impact"`
+	Detail string       `json:"detail,omitempty"`
+}
+
+// ParseChangeImpact accepts the CLI spellings of an impact level.
+func ParseChangeImpact(s string) (ChangeImpact, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "safe", "any":
+		return ImpactSafe, nil
+	case "potentially-breaking", "potential", "potentially_breaking":
+		return ImpactPotentiallyBreaking, nil
+	case "breaking":
+		return ImpactBreaking, nil
+	default:
+		return "", fmt.Errorf("unknown impact %q (expected breaking, potentially-breaking, or safe)", s)
+	}
+}
+
+// AtLeast reports whether i is as severe as (or more severe than) other.
+func (i ChangeImpact) AtLeast(other ChangeImpact) bool {
+	return impactRank(i) >= impactRank(other)
+}
+
+func impactRank(i ChangeImpact) int {
+	switch i {
+	case ImpactBreaking:
+		return 2
+	case ImpactPotentiallyBreaking:
+		return 1
+	case ImpactSafe:
+		return 0
+	default:
+		return -1
+	}
+}
+
+// AllChanges returns table-level changes (added/removed tables) followed by each table's classified changes.
+func (d SchemaDiff) AllChanges() []SchemaChange {
+	var out []SchemaChange
+	for _, name := range d.NewTables {
+		out = append(out, SchemaChange{Table: name, Kind: ChangeTableAdded, Impact: ImpactSafe})
+	}
+	for _, name := range d.RemovedTables {
+		out = append(out, SchemaChange{Table: name, Kind: ChangeTableRemoved, Impact: ImpactBreaking, Detail: "all records in the table are dropped"})
+	}
+	for _, t := range d.ChangedTables {
+		out = append(out, t.Changes...)
+	}
+	return out
+}
+
+// Impact returns the most severe impact across the diff (safe when there are no changes).
+func (d SchemaDiff) Impact() ChangeImpact {
+	worst := ImpactSafe
+	for _, c := range d.AllChanges() {
+		if impactRank(c.Impact) > impactRank(worst) {
+			worst = c.Impact
+		}
+	}
+	return worst
+}
+
+// ChangesAtLeast filters AllChanges to those at or above the given impact.
+func (d SchemaDiff) ChangesAtLeast(level ChangeImpact) []SchemaChange {
+	var out []SchemaChange
+	for _, c := range d.AllChanges() {
+		if c.Impact.AtLeast(level) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// classifyTableDiff fills diff.Changes from the populated sections of a table diff.
+func classifyTableDiff(diff *SchemaTableDiff) {
+	add := func(kind, target string, impact ChangeImpact, detail string) {
+		diff.Changes = append(diff.Changes, SchemaChange{Table: diff.Name, Kind: kind, Target: target, Impact: impact, Detail: detail})
+	}
+
+	if p := diff.Partition; p != nil {
+		add(ChangePartitionChanged, "", ImpactBreaking, fmt.Sprintf("partition %q -> %q requires repartitioning existing records", p.From, p.To))
+	}
+	if id := diff.Identifier; id != nil {
+		add(ChangeIdentifierChanged, "", ImpactBreaking, fmt.Sprintf("identifier %s -> %s changes how records are keyed", describeIdentifier(id.From), describeIdentifier(id.To)))
+	}
+
+	if a := diff.Attributes; a != nil {
+		for _, attr := range a.Added {
+			if attr.IsNullable {
+				add(ChangeAttributeAdded, attr.Name, ImpactSafe, "nullable attribute added")
+			} else {
+				add(ChangeAttributeAdded, attr.Name, ImpactPotentiallyBreaking, "non-nullable attribute added; existing records have no value")
+			}
+		}
+		for _, name := range a.Removed {
+			add(ChangeAttributeRemoved, name, ImpactBreaking, "attribute data is dropped")
+		}
+		for _, c := range a.Changed {
+			if c.From.Type != c.To.Type {
+				if strings.EqualFold(c.From.Type, c.To.Type) {
+					add(ChangeAttributeType, c.Name, ImpactSafe, fmt.Sprintf("type casing %s -> %s", c.From.Type, c.To.Type))
+				} else {
+					impact, detail := classifyTypeChange(c.From.Type, c.To.Type)
+					add(ChangeAttributeType, c.Name, impact, detail)
+				}
+			}
+			if c.From.IsNullable != c.To.IsNullable {
+				if c.To.IsNullable {
+					add(ChangeAttributeNullable, c.Name, ImpactSafe, "non-nullable -> nullable")
+				} else {
+					add(ChangeAttributeNullable, c.Name, ImpactBreaking, "nullable -> non-nullable; existing null values are rejected")
+				}
+			}
+		}
+	}
+
+	if idx := diff.Indexes; idx != nil {
+		for _, i := range idx.Added {
+			add(ChangeIndexAdded, i.Name, ImpactSafe, "")
+		}
+		for _, name := range idx.Removed {
+			add(ChangeIndexRemoved, name, ImpactPotentiallyBreaking, "queries relying on the index may slow down or stop matching")
+		}
+		for _, c := range idx.Changed {
+			if indexType(c.From) != indexType(c.To) {
+				add(ChangeIndexChanged, c.Name, ImpactPotentiallyBreaking, fmt.Sprintf("index type %s -> %s changes query semantics and forces a rebuild", indexType(c.From), indexType(c.To)))
+			} else {
+				add(ChangeIndexChanged, c.Name, ImpactSafe, "minimum score changed")
+			}
+		}
+	}
+
+	if r := diff.Resolvers; r != nil {
+		for _, res := range r.Added {
+			add(ChangeResolverAdded, res.Name, ImpactSafe, "")
+		}
+		for _, name := range r.Removed {
+			add(ChangeResolverRemoved, name, ImpactBreaking, "clients resolving this relationship will fail")
+		}
+		for _, c := range r.Changed {
+			add(ChangeResolverChanged, c.Name, ImpactPotentiallyBreaking, "resolver query changed")
+		}
+	}
+
+	if t := diff.Triggers; t != nil {
+		for _, trg := range t.Added {
+			add(ChangeTriggerAdded, trg.Name, ImpactPotentiallyBreaking, "new trigger runs on "+trg.Event)
+		}
+		for _, name := range t.Removed {
+			add(ChangeTriggerRemoved, name, ImpactPotentiallyBreaking, "trigger side effects no longer run")
+		}
+		for _, c := range t.Changed {
+			add(ChangeTriggerChanged, c.Name, ImpactPotentiallyBreaking, "trigger event or body changed")
+		}
+	}
+}
+
+// numericWidth orders numeric types so widening conversions can be detected.
+var numericWidth = map[string]int{
+	"byte":    1,
+	"short":   2,
+	"int":     3,
+	"integer": 3,
+	"long":    4,
+}
+
+var floatWidth = map[string]int{
+	"float":  1,
+	"double": 2,
+}
+
+// classifyTypeChange treats lossless widening as safe, conversion to String as potentially breaking,
+// and everything else (narrowing or unrelated types) as breaking.
+func classifyTypeChange(from, to string) (ChangeImpact, string) {
+	f := strings.ToLower(strings.TrimSpace(from))
+	t := strings.ToLower(strings.TrimSpace(to))
+	detail := fmt.Sprintf("type %s -> %s", from, to)
+
+	if fw, ok := numericWidth[f]; ok {
+		if tw, ok := numericWidth[t]; ok {
+			if tw > fw {
+				return ImpactSafe, detail + " (widening)"
+			}
+			if tw == fw {
+				return ImpactSafe, detail + " (same width)"
+			}
+			return ImpactBreaking, detail + " (narrowing; values may overflow)"
+		}
+		if _, ok := floatWidth[t]; ok {
+			return ImpactPotentiallyBreaking, detail + " (integer to floating point may lose precision)"
+		}
+	}
+	if fw, ok := floatWidth[f]; ok {
+		if tw, ok := floatWidth[t]; ok && tw > fw {
+			return ImpactSafe, detail + " (widening)"
+		}
+		if _, ok := floatWidth[t]; ok {
+			return ImpactBreaking, detail + " (narrowing; values may lose precision)"
+		}
+	}
+	if (f == "date" && t == "timestamp") || (f == "timestamp" && t == "date") {
+		return ImpactPotentiallyBreaking, detail
+	}
+	if t == "string" {
+		return ImpactPotentiallyBreaking, detail + " (values are stringified)"
+	}
+	return ImpactBreaking, detail + " (incompatible types)"
+}
+
+func indexType(i SchemaIndex) string {
+	if i.Type == "" {
+		return "DEFAULT"
+	}
+	return i.Type
+}
+
+func describeIdentifier(id *SchemaIdentifier) string {
+	if id == nil {
+		return "<none>"
+	}
+	parts := []string{id.Name}
+	if id.Type != "" {
+		parts = append(parts, id.Type)
+	}
+	if id.Generator != "" {
+		parts = append(parts, id.Generator)
+	}
+	return strings.Join(parts, "/")
+}
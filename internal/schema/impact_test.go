@@ -0,0 +1,121 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComputeSchemaDiff_ClassifiesChanges(t *testing.T) {
+	api := SchemaRevision{Tables: []SchemaTable{
+		{
+			Name:       "User",
+			Partition:  "tenant",
+			Identifier: &SchemaIdentifier{Name: "id", Type: "String"},
+			Attributes: []SchemaAttribute{
+				{Name: "id", Type: "String"},
+				{Name: "age", Type: "Long"},
+				{Name: "score", Type: "Int"},
+				{Name: "nickname", Type: "String", IsNullable: true},
+				{Name: "legacy", Type: "String"},
+			},
+			Indexes: []SchemaIndex{{Name: "nickname", Type: "DEFAULT"}},
+		},
+		{Name: "Audit"},
+	}}
+	local := SchemaUpsertRequest{Tables: []SchemaTable{
+		{
+			Name:       "User",
+			Partition:  "region",
+			Identifier: &SchemaIdentifier{Name: "id", Type: "String"},
+			Attributes: []SchemaAttribute{
+				{Name: "id", Type: "String"},
+				{Name: "age", Type: "Int"},
+				{Name: "score", Type: "Long"},
+				{Name: "nickname", Type: "String"},
+				{Name: "bio", Type: "String", IsNullable: true},
+			},
+			Indexes: []SchemaIndex{{Name: "nickname", Type: "LUCENE"}},
+		},
+		{Name: "Team"},
+	}}
+
+	diff := ComputeSchemaDiff(api, local)
+
+	want := map[string]ChangeImpact{
+		"Team:" + ChangeTableAdded:                 ImpactSafe,
+		"Audit:" + ChangeTableRemoved:              ImpactBreaking,
+		"User:" + ChangePartitionChanged:           ImpactBreaking,
+		"User.age:" + ChangeAttributeType:          ImpactBreaking,
+		"User.score:" + ChangeAttributeType:        ImpactSafe,
+		"User.nickname:" + ChangeAttributeNullable: ImpactBreaking,
+		"User.bio:" + ChangeAttributeAdded:         ImpactSafe,
+		"User.legacy:" + ChangeAttributeRemoved:    ImpactBreaking,
+		"User.nickname:" + ChangeIndexChanged:      ImpactPotentiallyBreaking,
+	}
+	got := map[string]ChangeImpact{}
+	for _, c := range diff.AllChanges() {
+		key := c.Table
+		if c.Target != "" {
+			key += "." + c.Target
+		}
+		got[key+":"+c.Kind] = c.Impact
+	}
+	for key, impact := range want {
+		if got[key] != impact {
+			t.Fatalf("%s impact = %q, want %q (all: %#v)", key, got[key], impact, got)
+		}
+	}
+
+	if diff.Impact() != ImpactBreaking {
+		t.Fatalf("overall impact = %q, want breaking", diff.Impact())
+	}
+	if n := len(diff.ChangesAtLeast(ImpactPotentiallyBreaking)); n != 6 {
+		t.Fatalf("changes at or above potentially-breaking = %d, want 6", n)
+	}
+}
+
+func TestClassifyTypeChange(t *testing.T) {
+	cases := []struct {
+		from, to string
+		want     ChangeImpact
+	}{
+		{"Int", "Long", ImpactSafe},
+		{"Integer", "Long", ImpactSafe},
+		{"Int", "Integer", ImpactSafe},
+		{"Integer", "Short", ImpactBreaking},
+		{"Integer", "Double", ImpactPotentiallyBreaking},
+		{"Integer", "String", ImpactPotentiallyBreaking},
+	}
+	for _, c := range cases {
+		if got, detail := classifyTypeChange(c.from, c.to); got != c.want {
+			t.Fatalf("classifyTypeChange(%q, %q) = %q (%s), want %q", c.from, c.to, got, detail, c.want)
+		}
+	}
+}
+
+func TestParseChangeImpact(t *testing.T) {
+	for in, want := range map[string]ChangeImpact{
+		"breaking":             ImpactBreaking,
+		"Potentially-Breaking": ImpactPotentiallyBreaking,
+		"safe":                 ImpactSafe,
+	} {
+		got, err := ParseChangeImpact(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseChangeImpact(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseChangeImpact("destructive"); err == nil {
+		t.Fatalf("expected error for unknown impact")
+	}
+}
+
+func TestFormatSchemaDiff_YAMLIncludesClassifiedChanges(t *testing.T) {
+	api := SchemaRevision{Tables: []SchemaTable{{Name: "User", Attributes: []SchemaAttribute{{Name: "age", Type: "Long"}}}}}
+	local := SchemaUpsertRequest{Tables: []SchemaTable{{Name: "User", Attributes: []SchemaAttribute{{Name: "age", Type: "Int"}}}}}
+	out := FormatSchemaDiff(ComputeSchemaDiff(api, local), "onyx.schema.json")
+	for _, want := range []string{"changes:", "kind: attribute-type-changed", "target: age", "impact: breaking"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("YAML diff missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// IncludeKey is the manifest key listing table files: glob patterns relative to the manifest,
+// e.g. "include": ["tables/*.json"]. Each matched file holds one table object or an array of them.
+const IncludeKey = "include"
+
+// ManifestNames are the files looked for when a schema path is a directory.
+var ManifestNames = []string{"onyx.schema.json", "onyx.schema.yaml", "onyx.schema.yml", "onyx.schema.toml"}
+
+// ErrIncludesUnsupported is returned when a manifest with includes cannot be resolved (e.g. git sources).
+var ErrIncludesUnsupported = errors.New("schema includes are only supported for files on disk")
+
+// FindManifest returns the manifest inside a split schema directory.
+func FindManifest(dir string) (string, error) {
+	for _, name := range ManifestNames {
+		p := filepath.Join(dir, name)
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("%s is a directory without a schema manifest (expected one of %s)", dir, strings.Join(ManifestNames, ", "))
+}
+
+// TableSource records where an included table was defined: File is slash-separated and relative
+// to the manifest directory, Pointer locates the table inside it ("" when the file is the table).
+// Inline tables have a zero TableSource.
+type TableSource struct {
+	File    string
+	Pointer string
+}
+
+// ResolveIncludes assembles a manifest (as JSON) into one schema document: tables from the included
+// files are appended after any inline tables and the include key is dropped. fsys is rooted at the
+// manifest's directory; nil means includes are unsupported. sources[i] describes table i.
+// Documents without includes are returned unchanged with nil sources.
+func ResolveIncludes(doc []byte, fsys fs.FS) ([]byte, []TableSource, error) {
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &top); err != nil {
+		return doc, nil, nil // not an object; leave errors to the schema parser
+	}
+	rawInclude, ok := top[IncludeKey]
+	if !ok {
+		return doc, nil, nil
+	}
+	if fsys == nil {
+		return nil, nil, ErrIncludesUnsupported
+	}
+	var patterns []string
+	if err := json.Unmarshal(rawInclude, &patterns); err != nil {
+		return nil, nil, fmt.Errorf("%q must be a list of file patterns: %w", IncludeKey, err)
+	}
+
+	tablesKey := "tables"
+	if _, hasTables := top["tables"]; !hasTables {
+		if _, hasEntities := top["entities"]; hasEntities {
+			tablesKey = "entities"
+		}
+	}
+	var tables []json.RawMessage
+	if raw, ok := top[tablesKey]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &tables); err != nil {
+			return nil, nil, fmt.Errorf("decode %s: %w", tablesKey, err)
+		}
+	}
+	sources := make([]TableSource, len(tables))
+
+	seen := map[string]bool{}
+	for _, pattern := range patterns {
+		pattern = path.Clean(filepath.ToSlash(pattern))
+		if !fs.ValidPath(pattern) {
+			return nil, nil, fmt.Errorf("include %q must be a relative path inside the manifest directory", pattern)
+		}
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, nil, fmt.Errorf("include %q: %w", pattern, err)
+		}
+		if len(matches) == 0 && !strings.ContainsAny(pattern, "*?[") {
+			return nil, nil, fmt.Errorf("include %q: file not found", pattern)
+		}
+		sort.Strings(matches)
+		for _, name := range matches {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			included, prefix, err := readIncludedTables(fsys, name)
+			if err != nil {
+				return nil, nil, err
+			}
+			for i, t := range included {
+				src := TableSource{File: name}
+				if prefix != "" || len(included) > 1 {
+					src.Pointer = fmt.Sprintf("%s/%d", prefix, i)
+				}
+				tables = append(tables, t)
+				sources = append(sources, src)
+			}
+		}
+	}
+
+	delete(top, IncludeKey)
+	encodedTables, err := json.Marshal(tables)
+	if err != nil {
+		return nil, nil, err
+	}
+	top[tablesKey] = encodedTables
+	out, err := json.Marshal(top)
+	return out, sources, err
+}
+
+// readIncludedTables decodes one included file: a table object, an array of tables, or an object
+// with a tables (or entities) array, which is how TOML files list several tables. prefix is the
+// JSON pointer of the array holding the tables ("" for a bare array or single table).
+func readIncludedTables(fsys fs.FS, name string) (tables []json.RawMessage, prefix string, err error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, "", fmt.Errorf("include %s: %w", name, err)
+	}
+	if data, err = DecodeSchemaFile(data, FileFormatForPath(name)); err != nil {
+		return nil, "", fmt.Errorf("include %s: %w", name, err)
+	}
+	if err := json.Unmarshal(data, &tables); err == nil {
+		return tables, "", nil
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, "", fmt.Errorf("include %s: expected a table object or an array of tables: %w", name, err)
+	}
+	for _, key := range []string{"tables", "entities"} {
+		if raw, ok := obj[key]; ok {
+			if err := json.Unmarshal(raw, &tables); err != nil {
+				return nil, "", fmt.Errorf("include %s: decode %s: %w", name, key, err)
+			}
+			return tables, "/" + key, nil
+		}
+	}
+	return []json.RawMessage{data}, "", nil
+}
+
+// SplitFile is one file of a split schema layout, relative to the target directory.
+type SplitFile struct {
+	Path string
+	Data []byte
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// SplitSchema turns a schema document (JSON) into a manifest plus one file per table under
+// tables/, all in the given file format. The manifest keeps every top-level key except the tables.
+func SplitSchema(doc []byte, format string) ([]SplitFile, error) {
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &top); err != nil {
+		return nil, fmt.Errorf("decode schema json: %w", err)
+	}
+	raw := top["tables"]
+	if len(raw) == 0 || string(raw) == "null" {
+		raw = top["entities"]
+	}
+	var tables []json.RawMessage
+	if len(raw) > 0 && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &tables); err != nil {
+			return nil, fmt.Errorf("decode tables: %w", err)
+		}
+	}
+	delete(top, "tables")
+	delete(top, "entities")
+	include, _ := json.Marshal([]string{"tables/*." + format})
+	top[IncludeKey] = include
+
+	manifestJSON, err := json.Marshal(top)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := EncodeSchemaFile(manifestJSON, format)
+	if err != nil {
+		return nil, err
+	}
+	files := []SplitFile{{Path: "onyx.schema." + format, Data: manifest}}
+
+	used := map[string]bool{}
+	for i, t := range tables {
+		var named struct {
+			Name string `json:"name"`
+		}
+		_ = json.Unmarshal(t, &named)
+		base := strings.Trim(unsafeFileChars.ReplaceAllString(named.Name, "_"), "._")
+		if base == "" {
+			base = fmt.Sprintf("table-%d", i+1)
+		}
+		name := base
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[strings.ToLower(name)] = true // case-insensitive filesystems
+		data, err := EncodeSchemaFile(t, format)
+		if err != nil {
+			return nil, fmt.Errorf("encode table %s: %w", named.Name, err)
+		}
+		files = append(files, SplitFile{Path: "tables/" + name + "." + format, Data: data})
+	}
+	return files, nil
+}
@@ -0,0 +1,100 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestResolveIncludes_AssemblesTables(t *testing.T) {
+	fsys := fstest.MapFS{
+		"tables/User.json":  {Data: []byte(`{"name":"User","attributes":[{"name":"id","type":"String"}]}`)},
+		"tables/Order.yaml": {Data: []byte("name: Order\nattributes:\n  - name: id\n    type: Long\n")},
+		"extra/misc.json":   {Data: []byte(`[{"name":"A"},{"name":"B"}]`)},
+		"tables/notes.txt":  {Data: []byte("ignored")},
+		"tables/Audit.toml": {Data: []byte("[[tables]]\nname = \"Audit\"\n")},
+	}
+	doc := `{"databaseId":"db1","tables":[{"name":"Inline"}],"include":["tables/*.json","tables/*.yaml","tables/*.toml","extra/misc.json","tables/User.json"]}`
+
+	out, sources, err := ResolveIncludes([]byte(doc), fsys)
+	if err != nil {
+		t.Fatalf("ResolveIncludes: %v", err)
+	}
+	var req SchemaUpsertRequest
+	if err := json.Unmarshal(out, &req); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tbl := range req.Tables {
+		names = append(names, tbl.Name)
+	}
+	if want := []string{"Inline", "User", "Order", "Audit", "A", "B"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("tables = %v, want %v", names, want)
+	}
+	if req.DatabaseID != "db1" || strings.Contains(string(out), IncludeKey) {
+		t.Fatalf("manifest keys not carried over correctly: %s", out)
+	}
+	wantSources := []TableSource{{}, {File: "tables/User.json"}, {File: "tables/Order.yaml"}, {File: "tables/Audit.toml", Pointer: "/tables/0"}, {File: "extra/misc.json", Pointer: "/0"}, {File: "extra/misc.json", Pointer: "/1"}}
+	if !reflect.DeepEqual(sources, wantSources) {
+		t.Fatalf("sources = %+v", sources)
+	}
+}
+
+func TestResolveIncludes_Errors(t *testing.T) {
+	if _, _, err := ResolveIncludes([]byte(`{"include":["tables/*.json"]}`), nil); !errors.Is(err, ErrIncludesUnsupported) {
+		t.Fatalf("nil fs: err = %v", err)
+	}
+	if _, _, err := ResolveIncludes([]byte(`{"include":["tables/Missing.json"]}`), fstest.MapFS{}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("missing include: err = %v", err)
+	}
+	if _, _, err := ResolveIncludes([]byte(`{"include":["../outside.json"]}`), fstest.MapFS{}); err == nil {
+		t.Fatal("expected an error for an include outside the manifest directory")
+	}
+	out, sources, err := ResolveIncludes([]byte(`{"tables":[]}`), nil)
+	if err != nil || sources != nil || string(out) != `{"tables":[]}` {
+		t.Fatalf("documents without includes should pass through: %s %v %v", out, sources, err)
+	}
+}
+
+func TestSplitSchema_RoundTripsThroughIncludes(t *testing.T) {
+	doc := `{"databaseId":"db1","tables":[{"name":"User","attributes":[{"name":"id","type":"String"}]},{"name":"user"},{"name":"Order Line"}]}`
+	files, err := SplitSchema([]byte(doc), FileFormatYAML)
+	if err != nil {
+		t.Fatalf("SplitSchema: %v", err)
+	}
+	fsys := fstest.MapFS{}
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.Path)
+		fsys[f.Path] = &fstest.MapFile{Data: f.Data}
+	}
+	if want := []string{"onyx.schema.yaml", "tables/User.yaml", "tables/user-2.yaml", "tables/Order_Line.yaml"}; !reflect.DeepEqual(paths, want) {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+
+	manifest, err := DecodeSchemaFile(files[0].Data, FileFormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _, err := ResolveIncludes(manifest, fsys)
+	if err != nil {
+		t.Fatalf("ResolveIncludes: %v", err)
+	}
+	var got, want SchemaUpsertRequest
+	_ = json.Unmarshal(out, &got)
+	_ = json.Unmarshal([]byte(doc), &want)
+	// tables come back in file name order
+	byName := func(tables []SchemaTable) map[string]SchemaTable {
+		m := map[string]SchemaTable{}
+		for _, tbl := range tables {
+			m[tbl.Name] = tbl
+		}
+		return m
+	}
+	if !reflect.DeepEqual(byName(got.Tables), byName(want.Tables)) || got.DatabaseID != "db1" {
+		t.Fatalf("split + include changed the schema: %s", out)
+	}
+}
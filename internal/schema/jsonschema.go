@@ -0,0 +1,150 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect emitted by FileJSONSchema.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchema is the subset of JSON Schema keywords used to describe schema files.
+type jsonSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	Ref         string                 `json:"$ref,omitempty"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Type        string                 `json:"type,omitempty"`
+	Enum        []string               `json:"enum,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Properties  map[string]*jsonSchema `json:"properties,omitempty"`
+	Items       *jsonSchema            `json:"items,omitempty"`
+	Deprecated  bool                   `json:"deprecated,omitempty"`
+	Defs        map[string]*jsonSchema `json:"$defs,omitempty"`
+}
+
+// localHintNote ends the description of keys only the CLI reads (see StripLocalHints).
+const localHintNote = " Local to the CLI: stripped before the schema is sent to the API and kept by `onyx schema get`."
+
+func jsRef(def string) *jsonSchema { return &jsonSchema{Ref: "#/$defs/" + def} }
+
+func jsList(def string) *jsonSchema { return &jsonSchema{Type: "array", Items: jsRef(def)} }
+
+func jsString(description string) *jsonSchema {
+	return &jsonSchema{Type: "string", Description: description}
+}
+
+func jsEnum(description string, values []string) *jsonSchema {
+	return &jsonSchema{Type: "string", Description: description, Enum: values}
+}
+
+// FileJSONSchema returns a draft 2020-12 JSON Schema for onyx.schema.json files (and their YAML
+// and TOML equivalents), including split manifests. Table files of a split schema can point at
+// its "#/$defs/table" definition. Unknown properties are allowed, as the CLI preserves them.
+// Enums hold the canonical spellings and, as JSON Schema enums do, match case-sensitively, while
+// LintSchema accepts attribute types in any case; onyx schema fmt rewrites them canonically.
+func FileJSONSchema() ([]byte, error) {
+	var attributeTypes []string
+	for _, t := range AttributeTypes {
+		attributeTypes = append(attributeTypes, canonicalAttributeTypes[t])
+	}
+
+	doc := &jsonSchema{
+		Schema:      JSONSchemaDraft,
+		Title:       "Onyx schema file",
+		Description: "Tables published to an Onyx database with `onyx schema publish`. Enums list canonical spellings and are case-sensitive, unlike `onyx schema lint`; `onyx schema fmt` fixes the casing.",
+		Type:        "object",
+		Properties: map[string]*jsonSchema{
+			"databaseId": jsString("Database the schema belongs to."),
+			"tables":     {Type: "array", Description: "Tables in the database.", Items: jsRef("table")},
+			"entities":   {Type: "array", Description: "Legacy name for tables.", Items: jsRef("table"), Deprecated: true},
+			IncludeKey: {
+				Type:        "array",
+				Description: "Glob patterns, relative to this file, of table files to include (split schemas).",
+				Items:       &jsonSchema{Type: "string"},
+			},
+			"meta": {
+				Type:        "object",
+				Description: "Revision metadata written by `onyx schema get`; ignored on publish.",
+				Properties: map[string]*jsonSchema{
+					"revisionId":  jsString("Schema revision ID."),
+					"createdAt":   jsString("When the revision was created."),
+					"publishedAt": jsString("When the revision was published."),
+				},
+			},
+		},
+		Defs: map[string]*jsonSchema{
+			"table": {
+				Type:     "object",
+				Required: []string{"name"},
+				Properties: map[string]*jsonSchema{
+					"name":       jsString("Table name."),
+					"partition":  jsString("Attribute used to partition records."),
+					"identifier": jsRef("identifier"),
+					"attributes": jsList("attribute"),
+					"indexes":    jsList("index"),
+					"resolvers":  jsList("resolver"),
+					"triggers":   jsList("trigger"),
+				},
+			},
+			"identifier": {
+				Type:        "object",
+				Description: "Primary key; name must match an attribute.",
+				Properties: map[string]*jsonSchema{
+					"name":      jsString("Identifier attribute name."),
+					"generator": jsEnum("How new identifiers are generated.", IdentifierGenerators),
+					"type":      jsEnum("Identifier type.", attributeTypes),
+				},
+			},
+			"attribute": {
+				Type:     "object",
+				Required: []string{"name", "type"},
+				Properties: map[string]*jsonSchema{
+					"name":       jsString("Attribute name."),
+					"type":       jsEnum("Attribute type (case-sensitive here; `onyx schema lint` accepts any case).", attributeTypes),
+					"isNullable": {Type: "boolean", Description: "Whether the attribute may be null."},
+					"shape": {
+						Description: "Fields of an embedded object for code generation: an attribute type (\"String\", \"String[]\"), an object of nested shapes, or a one-item array for lists." + localHintNote,
+					},
+				},
+			},
+			"index": {
+				Type:     "object",
+				Required: []string{"name"},
+				Properties: map[string]*jsonSchema{
+					"name":         jsString("Indexed attribute name."),
+					"type":         jsEnum("Index type (default DEFAULT).", IndexTypes),
+					"minimumScore": {Type: "number", Description: "Minimum match score for LUCENE indexes."},
+					"unique":       {Type: "boolean", Description: "Values are unique; generated Go clients get a single-row FindBy<Field> finder." + localHintNote},
+				},
+			},
+			"resolver": {
+				Type:     "object",
+				Required: []string{"name", "resolver"},
+				Properties: map[string]*jsonSchema{
+					"name":     jsString("Resolver name."),
+					"resolver": jsString("Resolver script."),
+					"returns":  jsString("Table the resolver returns, with [] for a list (e.g. \"Role[]\"), for typed Go clients when it cannot be inferred from the script." + localHintNote),
+				},
+			},
+			"trigger": {
+				Type:     "object",
+				Required: []string{"name", "event", "trigger"},
+				Properties: map[string]*jsonSchema{
+					"name":    jsString("Trigger name."),
+					"event":   jsEnum("Event that runs the trigger.", TriggerEvents),
+					"trigger": jsString("Trigger script."),
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
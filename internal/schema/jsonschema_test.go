@@ -0,0 +1,47 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFileJSONSchema_EnumeratesKnownValues(t *testing.T) {
+	out, err := FileJSONSchema()
+	if err != nil {
+		t.Fatalf("FileJSONSchema: %v", err)
+	}
+	var doc struct {
+		Schema string `json:"$schema"`
+		Defs   map[string]struct {
+			Properties map[string]struct {
+				Enum []string `json:"enum"`
+			} `json:"properties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if doc.Schema != JSONSchemaDraft {
+		t.Fatalf("$schema = %q", doc.Schema)
+	}
+	checks := []struct {
+		def, prop string
+		want      []string
+	}{
+		{"attribute", "type", []string{"String", "Timestamp", "EmbeddedObject"}},
+		{"identifier", "generator", IdentifierGenerators},
+		{"index", "type", IndexTypes},
+		{"trigger", "event", TriggerEvents},
+	}
+	for _, c := range checks {
+		enum := toSet(doc.Defs[c.def].Properties[c.prop].Enum)
+		for _, v := range c.want {
+			if !enum[v] {
+				t.Fatalf("%s.%s enum %v is missing %q", c.def, c.prop, doc.Defs[c.def].Properties[c.prop].Enum, v)
+			}
+		}
+	}
+	if n := len(doc.Defs["attribute"].Properties["type"].Enum); n != len(AttributeTypes) {
+		t.Fatalf("attribute types: got %d, want %d", n, len(AttributeTypes))
+	}
+}
@@ -0,0 +1,199 @@
+package schema
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LintSeverity ranks lint findings; errors make `onyx schema lint` exit non-zero.
+type LintSeverity string
+
+const (
+	SeverityError   LintSeverity = "error"
+	SeverityWarning LintSeverity = "warning"
+)
+
+// Lint rule identifiers (stable; safe to reference from CI configs).
+const (
+	RuleDuplicateTable       = "duplicate-table"
+	RuleDuplicateAttribute   = "duplicate-attribute"
+	RuleDuplicateIndex       = "duplicate-index"
+	RuleIdentifierAttribute  = "identifier-missing-attribute"
+	RuleUnknownAttributeType = "unknown-attribute-type"
+	RuleEmptyResolver        = "empty-resolver"
+	RuleUnknownTriggerEvent  = "unknown-trigger-event"
+	RulePartitionAttribute   = "partition-missing-attribute"
+)
+
+// AttributeTypes lists the attribute types the code generators map to concrete types (lowercase).
+// Keep in sync with codegen.mapGoType; anything else is generated as `any`.
+var AttributeTypes = []string{
+	"string", "text", "uuid",
+	"bool", "boolean",
+	"int", "integer", "long", "short", "byte",
+	"float", "double", "decimal", "number",
+	"date", "datetime", "timestamp", "timestamptz",
+	"json", "object", "record", "map", "embeddedobject",
+}
+
+// TriggerEvents lists the trigger events recognised by the Onyx runtime.
+var TriggerEvents = []string{
+	"PrePersist", "PostPersist",
+	"PreInsert", "PostInsert",
+	"PreUpdate", "PostUpdate",
+	"PreDelete", "PostDelete",
+}
+
+// IdentifierGenerators lists the identifier generators recognised by the Onyx runtime.
+var IdentifierGenerators = []string{"None", "UUID", "Sequence"}
+
+// IndexTypes lists the index types recognised by the Onyx runtime (an empty type means DEFAULT).
+var IndexTypes = []string{"DEFAULT", "LUCENE"}
+
+// LintIssue is a single finding with a JSON-pointer location inside File.
+type LintIssue struct {
+	Rule     string       `json:"rule"`
+	Severity LintSeverity `json:"severity"`
+	Message  string       `json:"message"`
+	File     string       `json:"file,omitempty"`
+	Pointer  string       `json:"pointer"`
+}
+
+// Location renders file#pointer for display.
+func (i LintIssue) Location() string {
+	if i.File == "" {
+		return "#" + i.Pointer
+	}
+	return i.File + "#" + i.Pointer
+}
+
+// LintOptions controls how issue locations are reported.
+type LintOptions struct {
+	File      string // schema file path recorded on each issue
+	TablesKey string // top-level key holding tables in the file ("tables" or legacy "entities")
+	// Sources maps tables assembled from split schema files back to those files (see ResolveIncludes).
+	// Tables without a source are reported against File.
+	Sources []TableSource
+}
+
+// LintSchema runs the offline rule set over a parsed schema. Issues follow table order.
+func LintSchema(req SchemaUpsertRequest, opts LintOptions) []LintIssue {
+	tablesKey := opts.TablesKey
+	if tablesKey == "" {
+		tablesKey = "tables"
+	}
+	tables := req.Tables
+	if len(tables) == 0 {
+		tables = req.Entities
+	}
+
+	// tableLocation returns the file and pointer prefix for table ti.
+	tableLocation := func(ti int) (string, string) {
+		if ti < len(opts.Sources) && opts.Sources[ti].File != "" {
+			src := opts.Sources[ti]
+			return path.Join(path.Dir(filepath.ToSlash(opts.File)), src.File), src.Pointer
+		}
+		return opts.File, fmt.Sprintf("/%s/%d", tablesKey, ti)
+	}
+
+	var issues []LintIssue
+	file := opts.File
+	report := func(rule string, sev LintSeverity, pointer, format string, args ...any) {
+		issues = append(issues, LintIssue{
+			Rule:     rule,
+			Severity: sev,
+			Message:  fmt.Sprintf(format, args...),
+			File:     file,
+			Pointer:  pointer,
+		})
+	}
+
+	knownTypes := toSet(AttributeTypes)
+	knownEvents := toSet(TriggerEvents)
+	seenTables := map[string]int{}
+
+	for ti, t := range tables {
+		var tp string
+		file, tp = tableLocation(ti)
+		if first, ok := seenTables[t.Name]; ok && t.Name != "" {
+			firstFile, firstPointer := tableLocation(first)
+			at := firstPointer
+			if firstFile != file {
+				at = firstFile + "#" + firstPointer
+			}
+			report(RuleDuplicateTable, SeverityError, tp+"/name", "table %q is already defined at %s", t.Name, at)
+		} else {
+			seenTables[t.Name] = ti
+		}
+
+		attrs := map[string]bool{}
+		seenAttrs := map[string]int{}
+		for ai, a := range t.Attributes {
+			ap := fmt.Sprintf("%s/attributes/%d", tp, ai)
+			attrs[a.Name] = true
+			if first, ok := seenAttrs[a.Name]; ok && a.Name != "" {
+				report(RuleDuplicateAttribute, SeverityError, ap+"/name", "attribute %q is already defined on %s at %s/attributes/%d", a.Name, t.Name, tp, first)
+			} else {
+				seenAttrs[a.Name] = ai
+			}
+			if !knownTypes[strings.ToLower(strings.TrimSpace(a.Type))] {
+				report(RuleUnknownAttributeType, SeverityWarning, ap+"/type", "attribute %s.%s has unknown type %q; generated clients will use an untyped value", t.Name, a.Name, a.Type)
+			}
+		}
+
+		if t.Identifier != nil && strings.TrimSpace(t.Identifier.Name) != "" && !attrs[t.Identifier.Name] {
+			report(RuleIdentifierAttribute, SeverityError, tp+"/identifier/name", "identifier %q on %s does not match any attribute", t.Identifier.Name, t.Name)
+		}
+
+		if p := strings.TrimSpace(t.Partition); p != "" && !attrs[p] {
+			report(RulePartitionAttribute, SeverityError, tp+"/partition", "partition %q on %s does not match any attribute", p, t.Name)
+		}
+
+		seenIdx := map[string]int{}
+		for ii, idx := range t.Indexes {
+			ip := fmt.Sprintf("%s/indexes/%d", tp, ii)
+			if first, ok := seenIdx[idx.Name]; ok && idx.Name != "" {
+				report(RuleDuplicateIndex, SeverityError, ip+"/name", "index %q is already defined on %s at %s/indexes/%d", idx.Name, t.Name, tp, first)
+			} else {
+				seenIdx[idx.Name] = ii
+			}
+		}
+
+		for ri, r := range t.Resolvers {
+			if strings.TrimSpace(r.Resolver) == "" {
+				report(RuleEmptyResolver, SeverityError, fmt.Sprintf("%s/resolvers/%d/resolver", tp, ri), "resolver %q on %s has an empty body", r.Name, t.Name)
+			}
+		}
+
+		for gi, g := range t.Triggers {
+			if !knownEvents[g.Event] {
+				report(RuleUnknownTriggerEvent, SeverityError, fmt.Sprintf("%s/triggers/%d/event", tp, gi), "trigger %q on %s has unrecognised event %q (expected one of %s)", g.Name, t.Name, g.Event, strings.Join(TriggerEvents, ", "))
+			}
+		}
+	}
+
+	return issues
+}
+
+// CountLintIssues returns the number of errors and warnings.
+func CountLintIssues(issues []LintIssue) (errs, warnings int) {
+	for _, i := range issues {
+		switch i.Severity {
+		case SeverityError:
+			errs++
+		case SeverityWarning:
+			warnings++
+		}
+	}
+	return errs, warnings
+}
+
+func toSet(values []string) map[string]bool {
+	m := make(map[string]bool, len(values))
+	for _, v := range values {
+		m[v] = true
+	}
+	return m
+}
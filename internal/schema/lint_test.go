@@ -0,0 +1,105 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const lintSchemaJSON = `{
+  "entities": [
+    {
+      "name": "User",
+      "identifier": {"name": "userId", "generator": "UUID", "type": "String"},
+      "partition": "tenant",
+      "attributes": [
+        {"name": "id", "type": "String"},
+        {"name": "email", "type": "String"},
+        {"name": "email", "type": "String"},
+        {"name": "payload", "type": "Blob"}
+      ],
+      "indexes": [
+        {"name": "email", "type": "DEFAULT"},
+        {"name": "email", "type": "LUCENE"}
+      ],
+      "resolvers": [{"name": "roles", "resolver": "  "}],
+      "triggers": [{"name": "audit", "event": "BeforeSave", "trigger": "log()"}]
+    },
+    {
+      "name": "User",
+      "identifier": {"name": "id"},
+      "attributes": [{"name": "id", "type": "String"}]
+    }
+  ]
+}`
+
+func TestLintSchema_ReportsEachRule(t *testing.T) {
+	var req SchemaUpsertRequest
+	if err := json.Unmarshal([]byte(lintSchemaJSON), &req); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	issues := LintSchema(req, LintOptions{File: "onyx.schema.json", TablesKey: "entities"})
+
+	want := map[string]string{
+		RuleDuplicateAttribute:   "/entities/0/attributes/2/name",
+		RuleUnknownAttributeType: "/entities/0/attributes/3/type",
+		RuleIdentifierAttribute:  "/entities/0/identifier/name",
+		RulePartitionAttribute:   "/entities/0/partition",
+		RuleDuplicateIndex:       "/entities/0/indexes/1/name",
+		RuleEmptyResolver:        "/entities/0/resolvers/0/resolver",
+		RuleUnknownTriggerEvent:  "/entities/0/triggers/0/event",
+		RuleDuplicateTable:       "/entities/1/name",
+	}
+	got := map[string]string{}
+	for _, i := range issues {
+		got[i.Rule] = i.Pointer
+		if i.File != "onyx.schema.json" {
+			t.Fatalf("issue %s missing file: %#v", i.Rule, i)
+		}
+	}
+	for rule, pointer := range want {
+		if got[rule] != pointer {
+			t.Fatalf("rule %s pointer = %q, want %q (issues: %#v)", rule, got[rule], pointer, issues)
+		}
+	}
+
+	errs, warnings := CountLintIssues(issues)
+	if errs != 7 || warnings != 1 {
+		t.Fatalf("counts = %d errors, %d warnings; want 7, 1", errs, warnings)
+	}
+}
+
+func TestLintSchema_CleanSchema(t *testing.T) {
+	req := SchemaUpsertRequest{Tables: []SchemaTable{{
+		Name:       "Team",
+		Identifier: &SchemaIdentifier{Name: "id", Type: "String"},
+		Attributes: []SchemaAttribute{{Name: "id", Type: "String"}, {Name: "createdAt", Type: "Timestamp"}},
+		Triggers:   []SchemaTrigger{{Name: "stamp", Event: "PrePersist", Trigger: "this.createdAt = new Date()"}},
+	}}}
+	if issues := LintSchema(req, LintOptions{}); len(issues) != 0 {
+		t.Fatalf("expected no issues, got %#v", issues)
+	}
+}
+
+func TestLintSchema_ReportsIncludedFiles(t *testing.T) {
+	req := SchemaUpsertRequest{Tables: []SchemaTable{
+		{Name: "Team", Partition: "org"},
+		{Name: "Team", Attributes: []SchemaAttribute{{Name: "id", Type: "Widget"}}},
+	}}
+	sources := []TableSource{{}, {File: "tables/Team.json"}}
+	issues := LintSchema(req, LintOptions{File: "schema/onyx.schema.json", Sources: sources})
+
+	got := map[string]LintIssue{}
+	for _, i := range issues {
+		got[i.Rule] = i
+	}
+	if i := got[RulePartitionAttribute]; i.Location() != "schema/onyx.schema.json#/tables/0/partition" {
+		t.Fatalf("inline table location = %q", i.Location())
+	}
+	if i := got[RuleUnknownAttributeType]; i.Location() != "schema/tables/Team.json#/attributes/0/type" {
+		t.Fatalf("included table location = %q", i.Location())
+	}
+	if i := got[RuleDuplicateTable]; i.Location() != "schema/tables/Team.json#/name" || !strings.Contains(i.Message, "schema/onyx.schema.json#/tables/0") {
+		t.Fatalf("duplicate table issue = %#v", i)
+	}
+}
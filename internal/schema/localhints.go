@@ -0,0 +1,124 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// localHints are the keys, per table section, that only onyx itself reads (code generation
+// hints). The Schema API does not know them, so they are stripped before a schema is sent and
+// restored from the local file when a pull overwrites it.
+var localHints = map[string]string{
+	"attributes": "shape",
+	"indexes":    "unique",
+	"resolvers":  "returns",
+}
+
+// StripLocalHints removes attribute "shape", index "unique" and resolver "returns" from decoded
+// schema JSON: a schema object, an array of tables or a single table.
+func StripLocalHints(v any) {
+	eachTable(v, func(t map[string]any) {
+		for section, key := range localHints {
+			items, _ := t[section].([]any)
+			for _, item := range items {
+				if m, ok := item.(map[string]any); ok {
+					delete(m, key)
+				}
+			}
+		}
+	})
+}
+
+// RestoreLocalHints copies the local-only hints (see StripLocalHints) of the local schema JSON
+// into the pulled schema JSON, matching tables and their attributes, indexes and resolvers by
+// name. Entries missing from either side are left alone.
+func RestoreLocalHints(pulled, local []byte) ([]byte, error) {
+	decode := func(data []byte) (any, error) {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, fmt.Errorf("decode schema json: %w", err)
+		}
+		return v, nil
+	}
+	dst, err := decode(pulled)
+	if err != nil {
+		return nil, err
+	}
+	src, err := decode(local)
+	if err != nil {
+		return nil, err
+	}
+
+	// hints[table][section][name] is the hint value of a local entry
+	hints := map[string]map[string]map[string]any{}
+	eachTable(src, func(t map[string]any) {
+		name, _ := t["name"].(string)
+		for section, key := range localHints {
+			items, _ := t[section].([]any)
+			for _, item := range items {
+				m, _ := item.(map[string]any)
+				hint, ok := m[key]
+				if !ok {
+					continue
+				}
+				if hints[name] == nil {
+					hints[name] = map[string]map[string]any{}
+				}
+				if hints[name][section] == nil {
+					hints[name][section] = map[string]any{}
+				}
+				itemName, _ := m["name"].(string)
+				hints[name][section][itemName] = hint
+			}
+		}
+	})
+	if len(hints) == 0 {
+		return pulled, nil
+	}
+	eachTable(dst, func(t map[string]any) {
+		name, _ := t["name"].(string)
+		for section, key := range localHints {
+			items, _ := t[section].([]any)
+			for _, item := range items {
+				m, ok := item.(map[string]any)
+				if !ok {
+					continue
+				}
+				itemName, _ := m["name"].(string)
+				if hint, ok := hints[name][section][itemName]; ok {
+					if _, set := m[key]; !set {
+						m[key] = hint
+					}
+				}
+			}
+		}
+	})
+	out, err := json.Marshal(dst)
+	if err != nil {
+		return nil, fmt.Errorf("encode schema json: %w", err)
+	}
+	return out, nil
+}
+
+// eachTable calls fn for every table of decoded schema JSON, in both "tables" and legacy
+// "entities"; v may also be an array of tables or a single table, as in split schema files.
+func eachTable(v any, fn func(map[string]any)) {
+	switch t := v.(type) {
+	case []any:
+		for _, item := range t {
+			if m, ok := item.(map[string]any); ok {
+				fn(m)
+			}
+		}
+	case map[string]any:
+		if _, isTable := t["name"]; isTable && t["tables"] == nil && t["entities"] == nil {
+			fn(t)
+			return
+		}
+		eachTable(t["tables"], fn)
+		eachTable(t["entities"], fn)
+	}
+}
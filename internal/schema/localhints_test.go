@@ -0,0 +1,47 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStripLocalHints(t *testing.T) {
+	var v any
+	doc := `{"tables":[{"name":"User",
+  "attributes":[{"name":"profile","type":"EmbeddedObject","shape":{"city":"String"}}],
+  "indexes":[{"name":"email","type":"DEFAULT","unique":true}],
+  "resolvers":[{"name":"roles","resolver":"db.from(\"Role\")","returns":"Role[]"}]}]}`
+	if err := json.Unmarshal([]byte(doc), &v); err != nil {
+		t.Fatal(err)
+	}
+	StripLocalHints(v)
+	got, _ := json.Marshal(v)
+	want := `{"tables":[{"attributes":[{"name":"profile","type":"EmbeddedObject"}],"indexes":[{"name":"email","type":"DEFAULT"}],"name":"User","resolvers":[{"name":"roles","resolver":"db.from(\"Role\")"}]}]}`
+	if string(got) != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRestoreLocalHints(t *testing.T) {
+	local := `{"tables":[{"name":"User",
+  "attributes":[{"name":"profile","type":"EmbeddedObject","shape":{"city":"String"}},{"name":"gone","shape":"String"}],
+  "indexes":[{"name":"email","unique":true}],
+  "resolvers":[{"name":"roles","returns":"Role[]"}]}]}`
+	pulled := `{"tables":[{"name":"User","attributes":[{"name":"profile","type":"EmbeddedObject"},{"name":"age","type":"Int"}],` +
+		`"indexes":[{"name":"email","type":"DEFAULT"}],"resolvers":[{"name":"roles","resolver":"x"}]},{"name":"Role"}]}`
+	got, err := RestoreLocalHints([]byte(pulled), []byte(local))
+	if err != nil {
+		t.Fatalf("RestoreLocalHints: %v", err)
+	}
+	canonical, err := CanonicalizeSchema(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"tables":[{"name":"Role"},{"name":"User",` +
+		`"attributes":[{"name":"age","type":"Int"},{"name":"profile","type":"EmbeddedObject","shape":{"city":"String"}}],` +
+		`"indexes":[{"name":"email","type":"DEFAULT","unique":true}],` +
+		`"resolvers":[{"name":"roles","resolver":"x","returns":"Role[]"}]}]}`
+	if string(canonical) != want {
+		t.Fatalf("got\n%s\nwant\n%s", canonical, want)
+	}
+}
@@ -86,6 +86,7 @@ type SchemaTableDiff struct {
 	Indexes    *IndexChanges     `json:"indexes,omitempty"`
 	Resolvers  *ResolverChanges  `json:"resolvers,omitempty"`
 	Triggers   *TriggerChanges   `json:"triggers,omitempty"`
+	Changes    []SchemaChange    `json:"changes,omitempty"` // classified view of the sections above
 }
 
 type PartitionChange struct {